@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+var AboutCommand = tempest.Command{
+	Type:                tempest.CHAT_INPUT_COMMAND_TYPE,
+	Name:                "about",
+	Description:         "Show the version of the bot and the error catalogs it serves",
+	SlashCommandHandler: handleAbout,
+}
+
+// version is the build of the bot. Tests replace it to get a stable value.
+var version = util.Version
+
+func handleAbout(itx *tempest.CommandInteraction) {
+	reply(itx, tempest.ResponseMessageData{
+		Embeds: []tempest.Embed{createAboutEmbed()},
+	})
+}
+
+func createAboutEmbed() tempest.Embed {
+	lines := []string{fmt.Sprintf("Version `%s`", version())}
+	overridden := []string{}
+
+	for _, entry := range repoInstance.Manifest {
+		if entry.Overridden {
+			overridden = append(overridden, entry.Name)
+		}
+	}
+
+	// the data directory is left out, it is of no use to users and tells
+	// them about the host
+	if len(overridden) > 0 {
+		lines = append(lines, "Catalog files from the data directory override the embedded "+strings.Join(overridden, ", ")+" catalogs")
+	} else {
+		lines = append(lines, "Serving the embedded catalog files")
+	}
+
+	embed := tempest.Embed{
+		Title:       "About errorbot",
+		Description: strings.Join(lines, "\n"),
+	}
+
+	for _, entry := range repoInstance.Manifest {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  entry.Name,
+			Value: formatManifestEntry(entry),
+		})
+	}
+
+	return embed
+}
+
+func formatManifestEntry(entry repo.ManifestEntry) string {
+	lines := []string{fmt.Sprintf("`%s`", entry.File), fmt.Sprintf("Entries: %d", entry.Entries)}
+
+	if entry.Source != "" {
+		lines = append(lines, "Source: "+entry.Source)
+	}
+
+	if entry.SDK != "" {
+		lines = append(lines, "SDK: "+entry.SDK)
+	}
+
+	if entry.Generated != "" {
+		lines = append(lines, "Generated: "+entry.Generated)
+	}
+
+	if entry.Overridden {
+		lines = append(lines, "Overridden by the data directory")
+	} else if entry.SHA256 != "" {
+		lines = append(lines, fmt.Sprintf("SHA-256: `%s`", entry.SHA256[:min(len(entry.SHA256), 12)]))
+	} else {
+		lines = append(lines, "Checksum not verified")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+func TestHandleAbout(t *testing.T) {
+	oldVersion := version
+	t.Cleanup(func() {
+		version = oldVersion
+	})
+
+	version = func() string { return "814a590f2c3e" }
+
+	tests := []struct {
+		name       string
+		overridden []string
+		expected   string
+	}{
+		{name: "embedded", overridden: nil, expected: "Serving the embedded catalog files"},
+		{name: "data directory", overridden: []string{"Win32 error"}, expected: "Catalog files from the data directory override the embedded Win32 error catalogs"},
+		{name: "several overrides", overridden: []string{"HRESULT", "Win32 error"}, expected: "override the embedded HRESULT, Win32 error catalogs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(itx *tempest.CommandInteraction) {
+				for i, entry := range repoInstance.Manifest {
+					repoInstance.Manifest[i].Overridden = slices.Contains(tt.overridden, entry.Name)
+				}
+
+				handleAbout(itx)
+			}
+
+			response := expectEmbedReply(t, runCommand(t, handler, "about"))
+			embed := response.Embeds[0]
+
+			if !strings.Contains(embed.Description, "`814a590f2c3e`") || !strings.Contains(embed.Description, tt.expected) {
+				t.Errorf("description = %q, expected the version and %q", embed.Description, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleAbout_Catalogs(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleAbout, "about"))
+	embed := response.Embeds[0]
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "HRESULT", expected: "`hresult.yml`\nEntries: 1\nSource: winerror.h\nSDK: 10.0.26100.0\nGenerated: 2026-10-17\nSHA-256: `33bec4ac7c22`"},
+		{name: "Win32 error", expected: "`win32error.yml`\nEntries: 2\nChecksum not verified"},
+	}
+
+	if len(embed.Fields) != len(tests) {
+		t.Errorf("embed has %d fields, expected %d", len(embed.Fields), len(tests))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if value, _ := fieldValue(embed, tt.name); value != tt.expected {
+				t.Errorf("field %q = %q, expected %q", tt.name, value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleAbout_OverriddenCatalog(t *testing.T) {
+	handler := func(itx *tempest.CommandInteraction) {
+		repoInstance.Manifest[0].Overridden = true
+
+		handleAbout(itx)
+	}
+
+	embed := expectEmbedReply(t, runCommand(t, handler, "about")).Embeds[0]
+
+	if value, _ := fieldValue(embed, "HRESULT"); value != "`hresult.yml`\nEntries: 1\nSource: winerror.h\nSDK: 10.0.26100.0\nGenerated: 2026-10-17\nOverridden by the data directory" {
+		t.Errorf("field HRESULT = %q, expected the override instead of the checksum", value)
+	}
+}
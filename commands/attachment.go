@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+var attachmentClient = &http.Client{Timeout: 30 * time.Second}
+
+// downloadAttachment fetches at most limit bytes of an attachment. Tests
+// replace it to serve attachments without the network.
+var downloadAttachment = func(url string, limit int64) ([]byte, error) {
+	response, err := attachmentClient.Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(response.Body, limit))
+}
+
+// attachmentOption returns the attachment given as the named option.
+func attachmentOption(itx *tempest.CommandInteraction, name string) (tempest.Attachment, bool) {
+	// attachment options hold the ID of an attachment in the resolved data
+	value, ok := stringOption(itx, name)
+
+	if !ok || itx.Data.Resolved == nil {
+		return tempest.Attachment{}, false
+	}
+
+	id, err := tempest.StringToSnowflake(value)
+
+	if err != nil {
+		return tempest.Attachment{}, false
+	}
+
+	attachment, ok := itx.Data.Resolved.Attachments[id]
+
+	return attachment, ok
+}
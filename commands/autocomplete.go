@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+// Discord limits on autocomplete choices
+const (
+	maxChoices          = 25
+	maxChoiceNameLength = 100
+)
+
+// autoCompleteCode creates an autocomplete handler suggesting symbolic names
+// and codes from the catalogs of the given kinds, or from all catalogs if no
+// kinds are given.
+func autoCompleteCode(kinds ...repo.Kind) func(tempest.CommandInteraction) []tempest.CommandOptionChoice {
+	return func(itx tempest.CommandInteraction) []tempest.CommandOptionChoice {
+		_, value := itx.GetFocusedValue()
+		prefix, _ := value.(string)
+
+		return suggestCodes(prefix, kinds...)
+	}
+}
+
+func suggestCodes(prefix string, kinds ...repo.Kind) []tempest.CommandOptionChoice {
+	choices := []tempest.CommandOptionChoice{}
+
+	for _, match := range repoInstance.Suggest(prefix, maxChoices, kinds...) {
+		// the name is what gets looked up once the choice is submitted,
+		// so it must not be truncated
+		if len(match.Name) > maxChoiceNameLength {
+			continue
+		}
+
+		name := fmt.Sprintf("%s (0x%08X)", match.Name, match.Code)
+
+		if len(name) > maxChoiceNameLength {
+			name = match.Name
+		}
+
+		choices = append(choices, tempest.CommandOptionChoice{
+			Name:  name,
+			Value: match.Name,
+		})
+	}
+
+	return choices
+}
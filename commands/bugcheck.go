@@ -1,7 +1,11 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
 
 	tempest "github.com/amatsagu/tempest"
@@ -14,68 +18,229 @@ var BugCheckCommand = tempest.Command{
 	Name:        "bugcheck",
 	Description: "Look up a Windows NT bug check code",
 	Options: []tempest.CommandOption{
+		{
+			Type:         tempest.STRING_OPTION_TYPE,
+			Name:         "code",
+			Description:  "Bug check code",
+			AutoComplete: true,
+		},
+		{
+			Type:        tempest.STRING_OPTION_TYPE,
+			Name:        "p1",
+			Description: "First parameter",
+		},
 		{
 			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "code",
-			Description: "Bug check code",
-			Required:    true,
+			Name:        "p2",
+			Description: "Second parameter",
+		},
+		{
+			Type:        tempest.STRING_OPTION_TYPE,
+			Name:        "p3",
+			Description: "Third parameter",
+		},
+		{
+			Type:        tempest.STRING_OPTION_TYPE,
+			Name:        "p4",
+			Description: "Fourth parameter",
+		},
+		{
+			Type:        tempest.STRING_OPTION_TYPE,
+			Name:        "line",
+			Description: "Bug check line from a blue screen, the event log or the debugger",
 		},
 	},
+	AutoCompleteHandler: autoCompleteCode(repo.KindBugCheck),
 	SlashCommandHandler: handleBugCheck,
 }
 
+const bugCheckLineHelp = "Paste a line such as `STOP: 0x0000007E (0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740)`, " +
+	"`The bugcheck was: 0x0000009f (0x3, ...)`, `BugCheck 133, {1, 1e00, ...}` " +
+	"or the `!analyze` header along with its `Arg1:` to `Arg4:` lines."
+
 func handleBugCheck(itx *tempest.CommandInteraction) {
-	value := itx.Data.Options[0].Value.(string)
-	codes, err := util.ParseCode(value)
+	if line, ok := stringOption(itx, "line"); ok {
+		handleBugCheckLine(itx, line)
+		return
+	}
+
+	value, _ := stringOption(itx, "code")
+	codes, reading, err := parseCode(value)
 
-	var response tempest.ResponseMessageData
 	var matches []repo.BugCheck
 
 	if err != nil {
-		matches = repoInstance.BugCheck.FindBugCheckString(value)
+		matches = repoInstance.FindBugCheckName(value)
 	} else {
-		matches = repoInstance.BugCheck.FindBugCheckCode(codes[0])
+		matches = repoInstance.FindBugCheckCode(codes[0])
+	}
+
+	if len(matches) == 0 {
+		if err != nil {
+			replyInvalidCode(itx, value, err)
+		} else {
+			replyError(itx, "Could not find bug check code %s (`0x%08X`)", value, codes[0])
+		}
+
+		return
 	}
 
-	if len(matches) > 0 {
-		match := matches[0]
+	values, given, ok := bugCheckParameters(itx)
 
-		embed := tempest.Embed{
-			Title:       match.Name,
-			Description: match.Description,
-			Fields: []tempest.EmbedField{
-				{
-					Name:  "Bugcheck code",
-					Value: fmt.Sprintf("`0x%08X`", match.Code),
-				},
-			},
+	if !ok {
+		return
+	}
+
+	replyBugCheck(itx, matches[0], values, given, reading)
+}
+
+func handleBugCheckLine(itx *tempest.CommandInteraction, text string) {
+	line, err := util.ParseBugCheckLine(text)
+
+	if err != nil {
+		slog.Info("could not understand bug check line", "command", itx.Data.Name, "value", text, "error", err)
+		replyError(itx, "Could not find a bug check code with its parameters in the line.\n%s", bugCheckLineHelp)
+		return
+	}
+
+	// the line has the code and the parameters, so options that say
+	// otherwise would be ignored
+	if value, ok := stringOption(itx, "code"); ok && !bugCheckCodeMatches(value, line.Code) {
+		replyError(itx, "The line has bug check `0x%08X`, but the code option is %s. Give either the line or the code.", line.Code, inlineCode(value))
+		return
+	}
+
+	for i := range line.Parameters {
+		if _, ok := stringOption(itx, fmt.Sprintf("p%d", i+1)); ok {
+			replyError(itx, "The line already has the parameters. Give either the line or the p1 to p4 options.")
+			return
+		}
+	}
+
+	matches := repoInstance.FindBugCheckCode(line.Code)
+
+	if len(matches) == 0 {
+		replyError(itx, "Could not find bug check code `0x%08X`", line.Code)
+		return
+	}
+
+	reading := fmt.Sprintf("-# Read bug check `0x%08X` from the %s.", line.Code, line.Format)
+
+	replyBugCheck(itx, matches[0], line.Parameters, true, reading)
+}
+
+// bugCheckCodeMatches reports whether a code option stands for the given bug
+// check code, by number or by name.
+func bugCheckCodeMatches(value string, code uint32) bool {
+	if codes, _, err := parseCode(value); err == nil {
+		return slices.Contains(codes, code)
+	}
+
+	for _, match := range repoInstance.FindBugCheckName(value) {
+		if match.Code == code {
+			return true
 		}
+	}
 
-		if len(match.Parameters) > 0 {
-			parameters := ""
+	return false
+}
 
-			for i, parameter := range match.Parameters {
-				parameters = fmt.Sprintf("%s%d. %s\n", parameters, i, strings.ReplaceAll(parameter, "\n", "\n   "))
-			}
+// replyBugCheck describes a bug check along with its parameters if they
+// were given, or their documentation otherwise.
+func replyBugCheck(itx *tempest.CommandInteraction, match repo.BugCheck, values [4]uint64, given bool, reading string) {
+	var response tempest.ResponseMessageData
 
-			if len(parameters) < 1024 {
-				embed.Fields = append(embed.Fields, tempest.EmbedField{
-					Name:  "Parameters",
-					Value: parameters,
-				})
-			}
+	embed := tempest.Embed{
+		Title:       match.Name,
+		Description: match.Description,
+		Fields: []tempest.EmbedField{
+			{
+				Name:  "Bugcheck code",
+				Value: fmt.Sprintf("`0x%08X`", match.Code),
+			},
+		},
+	}
+
+	if given {
+		for i, parameter := range repoInstance.DecodeBugCheck(match.Code, values) {
+			embed.Fields = append(embed.Fields, tempest.EmbedField{
+				Name:  fmt.Sprintf("Parameter %d", i+1),
+				Value: formatBugCheckParameter(parameter),
+			})
+		}
+	} else if len(match.Parameters) > 0 {
+		parameters := ""
+
+		for i, parameter := range match.Parameters {
+			parameters = fmt.Sprintf("%s%d. %s\n", parameters, i+1, strings.ReplaceAll(parameter, "\n", "\n   "))
 		}
 
 		embed.Fields = append(embed.Fields, tempest.EmbedField{
-			Name:  "Documentation",
-			Value: match.URL,
+			Name:  "Parameters",
+			Value: parameters,
 		})
+	}
 
-		response.Embeds = append(response.Embeds, embed)
+	embed.Fields = append(embed.Fields, tempest.EmbedField{
+		Name:  "Documentation",
+		Value: match.URL,
+	})
 
-	} else {
-		response.Content = fmt.Sprintf("Could not find bug check code %s (`0x%08X`)", value, codes[0])
+	response.Embeds = append(response.Embeds, embed)
+
+	response.Content = reading
+
+	reply(itx, response)
+}
+
+// bugCheckParameters reads the p1 to p4 options. Parameters that were not
+// given are zero. If a parameter cannot be read, the user is told and ok is
+// false.
+func bugCheckParameters(itx *tempest.CommandInteraction) (values [4]uint64, given bool, ok bool) {
+	for i := range values {
+		name := fmt.Sprintf("p%d", i+1)
+		value, found := stringOption(itx, name)
+
+		if !found {
+			continue
+		}
+
+		parameter, err := util.ParseParameter(value)
+
+		if err != nil {
+			slog.Info("could not understand parameter", "command", itx.Data.Name, "option", name, "value", value, "error", err)
+			replyError(itx, "Could not understand %s for `%s`: %s.\nParameters are read as hex, like in the debugger. Use the `0n` prefix for decimal numbers.", inlineCode(value), name, describeParameterError(err))
+			return values, false, false
+		}
+
+		values[i] = parameter
+		given = true
+	}
+
+	return values, given, true
+}
+
+func describeParameterError(err error) string {
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return "the number does not fit into 64 bits"
+	case errors.Is(err, strconv.ErrSyntax):
+		return "it is not a number"
+	}
+
+	return err.Error()
+}
+
+func formatBugCheckParameter(parameter repo.BugCheckParameter) string {
+	lines := []string{fmt.Sprintf("`0x%X`", parameter.Value)}
+
+	if parameter.Description != "" {
+		lines = append(lines, parameter.Description)
+	}
+
+	if parameter.Interpretation != "" {
+		lines = append(lines, "> "+parameter.Interpretation)
 	}
 
-	itx.SendReply(response, false, nil)
+	return strings.Join(lines, "\n")
 }
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+func TestHandleBugCheck_MalformedInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "garbage", value: "#$%", fragments: []string{"`#$%`", "neither a number nor a known symbolic name"}},
+		{name: "unknown name", value: "NOT_A_BUG_CHECK", fragments: []string{"`NOT_A_BUG_CHECK`"}},
+		{name: "negative overflow", value: "-99999999999", fragments: []string{"does not fit into a 32-bit code"}},
+		{name: "empty", value: "", fragments: []string{"No code was given"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleBugCheck, "bugcheck", "code", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleBugCheck_NotFound(t *testing.T) {
+	replies := runCommand(t, handleBugCheck, "bugcheck", "code", "0x999")
+	expectErrorReply(t, replies, "Could not find bug check code 0x999 (`0x00000999`)")
+}
+
+func TestHandleBugCheck_Found(t *testing.T) {
+	for _, value := range []string{"0x7E", "7e", "system_thread"} {
+		t.Run(value, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleBugCheck, "bugcheck", "code", value))
+
+			if response.Embeds[0].Title != "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED" {
+				t.Errorf("embed title = %q, expected %q", response.Embeds[0].Title, "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED")
+			}
+		})
+	}
+}
+
+func TestHandleBugCheck_LongParameters(t *testing.T) {
+	handler := func(itx *tempest.CommandInteraction) {
+		repoInstance = repo.New(repo.NewBugCheckCatalog("bug check", repo.BugCheckRepo{
+			{Code: 0x7E, Name: "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED", Parameters: []string{
+				strings.Repeat("The address that the exception occurred at\n", 20),
+				strings.Repeat("The exception record\n", 20),
+			}},
+		}))
+		repoInstance.BuildIndex()
+
+		handleBugCheck(itx)
+	}
+
+	response := expectEmbedReply(t, runCommand(t, handler, "bugcheck", "code", "0x7E"))
+	checkLimits(t, response.Embeds)
+
+	parameters := ""
+
+	for _, embed := range response.Embeds {
+		for _, field := range embed.Fields {
+			if strings.HasPrefix(field.Name, "Parameters") {
+				parameters += field.Value
+			}
+		}
+	}
+
+	if !strings.Contains(parameters, "The exception record") {
+		t.Errorf("parameters were left out of the reply")
+	}
+}
+
+func TestHandleBugCheck_Parameters(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleBugCheck, "bugcheck", "code", "0x7E", "p1", "c0000005", "p2", "fffff801`12345678"))
+
+	first, ok := fieldValue(response.Embeds[0], "Parameter 1")
+
+	if !ok {
+		t.Fatalf("no Parameter 1 field in %+v", response.Embeds[0].Fields)
+	}
+
+	for _, fragment := range []string{"`0xC0000005`", "The exception code that was not handled", "> STATUS_ACCESS_VIOLATION"} {
+		if !strings.Contains(first, fragment) {
+			t.Errorf("Parameter 1 = %q, expected it to contain %q", first, fragment)
+		}
+	}
+
+	if second, _ := fieldValue(response.Embeds[0], "Parameter 2"); !strings.HasPrefix(second, "`0xFFFFF80112345678`") {
+		t.Errorf("Parameter 2 = %q, expected the address", second)
+	}
+
+	if fourth, ok := fieldValue(response.Embeds[0], "Parameter 4"); !ok || fourth != "`0x0`" {
+		t.Errorf("Parameter 4 = %q, expected %q", fourth, "`0x0`")
+	}
+
+	if _, ok := fieldValue(response.Embeds[0], "Parameters"); ok {
+		t.Errorf("the parameter documentation was shown besides the decoded parameters")
+	}
+}
+
+func TestHandleBugCheck_InvalidParameter(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "garbage", value: "xyz", fragments: []string{"`p2`", "`xyz`", "it is not a number"}},
+		{name: "too long", value: "0x1ffffffffffffffff", fragments: []string{"does not fit into 64 bits"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleBugCheck, "bugcheck", "code", "0x7E", "p2", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleBugCheck_ParameterDocumentation(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleBugCheck, "bugcheck", "code", "0x7E"))
+
+	parameters, ok := fieldValue(response.Embeds[0], "Parameters")
+
+	if !ok || !strings.HasPrefix(parameters, "1. ") {
+		t.Errorf("Parameters = %q, expected a list starting at 1", parameters)
+	}
+}
+
+func TestHandleBugCheck_Line(t *testing.T) {
+	line := "The computer has rebooted from a bugcheck.  The bugcheck was: 0x0000007e (0xffffffffc0000005, 0xfffff80112345678, 0xffff8a0123456789, 0xffff8a0198765432)."
+	replies := runCommand(t, handleBugCheck, "bugcheck", "line", line)
+	response := expectEmbedReply(t, replies)
+
+	if response.Embeds[0].Title != "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED" {
+		t.Errorf("embed title = %q, expected %q", response.Embeds[0].Title, "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED")
+	}
+
+	if expected := "-# Read bug check `0x0000007E` from the STOP line."; response.Content != expected {
+		t.Errorf("content = %q, expected %q", response.Content, expected)
+	}
+
+	if first, _ := fieldValue(response.Embeds[0], "Parameter 1"); !strings.Contains(first, "STATUS_ACCESS_VIOLATION") {
+		t.Errorf("Parameter 1 = %q, expected the exception code to be decoded", first)
+	}
+
+	if fourth, _ := fieldValue(response.Embeds[0], "Parameter 4"); !strings.HasPrefix(fourth, "`0xFFFF8A0198765432`") {
+		t.Errorf("Parameter 4 = %q, expected the context record address", fourth)
+	}
+}
+
+func TestHandleBugCheck_InvalidLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "no bug check", value: "my computer crashed", fragments: []string{"Could not find a bug check code", "STOP: 0x0000007E"}},
+		{name: "unknown bug check", value: "BugCheck 999, {1, 2, 3, 4}", fragments: []string{"Could not find bug check code `0x00000999`"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleBugCheck, "bugcheck", "line", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleBugCheck_LineWithOptions(t *testing.T) {
+	line := "BugCheck 7E, {ffffffffc0000005, fffff80112345678, ffff8a0123456789, ffff8a0198765432}"
+
+	errorTests := []struct {
+		name      string
+		options   []string
+		fragments []string
+	}{
+		{name: "different code", options: []string{"code", "0xD1"}, fragments: []string{"The line has bug check `0x0000007E`", "`0xD1`"}},
+		{name: "different name", options: []string{"code", "IRQL_NOT_LESS_OR_EQUAL"}, fragments: []string{"The line has bug check `0x0000007E`"}},
+		{name: "parameters", options: []string{"p1", "0xC0000005"}, fragments: []string{"The line already has the parameters"}},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleBugCheck, "bugcheck", append([]string{"line", line}, tt.options...)...)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+
+	// a code that agrees with the line is not an error
+	for _, code := range []string{"0x7E", "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED"} {
+		t.Run("same code "+code, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleBugCheck, "bugcheck", "line", line, "code", code))
+
+			if response.Embeds[0].Title != "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED" {
+				t.Errorf("embed title = %q, expected SYSTEM_THREAD_EXCEPTION_NOT_HANDLED", response.Embeds[0].Title)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+var ConvertCommand = tempest.Command{
+	Type:        tempest.CHAT_INPUT_COMMAND_TYPE,
+	Name:        "convert",
+	Description: "Show an error code in every error namespace",
+	Options: []tempest.CommandOption{
+		{
+			Type:         tempest.STRING_OPTION_TYPE,
+			Name:         "code",
+			Description:  "Error code",
+			Required:     true,
+			AutoComplete: true,
+		},
+	},
+	AutoCompleteHandler: autoCompleteCode(),
+	SlashCommandHandler: handleConvert,
+}
+
+func handleConvert(itx *tempest.CommandInteraction) {
+	value, _ := stringOption(itx, "code")
+	codes, reading, err := parseCode(value)
+
+	if err != nil {
+		matches := findErrorsByName(value)
+
+		for _, kind := range repo.Kinds {
+			for _, match := range matches[kind] {
+				if !slices.Contains(codes, match.Code) {
+					codes = append(codes, match.Code)
+				}
+			}
+		}
+
+		if len(codes) == 0 {
+			replyInvalidCode(itx, value, err)
+			return
+		}
+	}
+
+	var response tempest.ResponseMessageData
+
+	for _, code := range codes {
+		response.Embeds = append(response.Embeds, createConversionEmbed(code))
+	}
+
+	response.Content = reading
+
+	reply(itx, response)
+}
+
+func createConversionEmbed(code uint32) tempest.Embed {
+	embed := tempest.Embed{
+		Title:       fmt.Sprintf("Conversions of 0x%08X", code),
+		Description: formatCodeForms(code),
+	}
+
+	for _, conversion := range repoInstance.Convert(code) {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  fmt.Sprintf("%s: %s", conversion.Kind, conversion.Expression),
+			Value: formatConversion(conversion),
+		})
+	}
+
+	return embed
+}
+
+// formatCodeForms shows a code in hex and as unsigned and signed decimal.
+func formatCodeForms(code uint32) string {
+	return fmt.Sprintf("`0x%08X` · unsigned `%d` · signed `%d`", code, code, int32(code))
+}
+
+func formatConversion(conversion repo.Conversion) string {
+	lines := []string{formatCodeForms(conversion.Code)}
+
+	for _, match := range conversion.Matches {
+		lines = append(lines, fmt.Sprintf("`%s`", match.Name))
+	}
+
+	if len(conversion.Matches) == 0 {
+		lines = append(lines, "No catalog entry")
+	}
+
+	return strings.Join(lines, "\n")
+}
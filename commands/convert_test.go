@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHandleConvert_MalformedInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "garbage", value: "not a code", fragments: []string{"`not a code`", "neither a number nor a known symbolic name"}},
+		{name: "unknown name", value: "ERROR_DOES_NOT_EXIST", fragments: []string{"`ERROR_DOES_NOT_EXIST`"}},
+		{name: "empty", value: "", fragments: []string{"No code was given"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleConvert, "convert", "code", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleConvert(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fields    []string
+		fragments []string
+	}{
+		{
+			name:      "Win32 name",
+			value:     "ERROR_ACCESS_DENIED",
+			fields:    []string{"Win32 error: 5", "HRESULT: HRESULT_FROM_WIN32(5)", "NTSTATUS: NTSTATUS_FROM_WIN32(5)"},
+			fragments: []string{"`0x80070005` · unsigned `2147942405` · signed `-2147024891`", "`0xC0070005`", "`ERROR_ACCESS_DENIED`"},
+		},
+		{
+			name:      "HRESULT",
+			value:     "0x80070002",
+			fields:    []string{"Win32 error: 2", "HRESULT: HRESULT_FROM_WIN32(2)", "NTSTATUS: NTSTATUS_FROM_WIN32(2)"},
+			fragments: []string{"`ERROR_FILE_NOT_FOUND`", "No catalog entry"},
+		},
+		{
+			name:      "NTSTATUS",
+			value:     "STATUS_ACCESS_VIOLATION",
+			fields:    []string{"NTSTATUS: 0xC0000005", "HRESULT: HRESULT_FROM_NT(0xC0000005)"},
+			fragments: []string{"`0xD0000005`", "signed `-1073741819`"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleConvert, "convert", "code", tt.value))
+			embed := response.Embeds[0]
+
+			names := []string{}
+			text := embed.Description
+
+			for _, field := range embed.Fields {
+				names = append(names, field.Name)
+				text += "\n" + field.Value
+			}
+
+			for _, field := range tt.fields {
+				if !strings.Contains(strings.Join(names, "\n"), field) {
+					t.Errorf("fields %q do not include %q", names, field)
+				}
+			}
+
+			for _, fragment := range tt.fragments {
+				if !strings.Contains(text, fragment) {
+					t.Errorf("embed does not contain %q:\n%s", fragment, text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/dump"
+)
+
+// maxDumpSize limits how much of an attachment is downloaded. Only the
+// headers are read, so small memory dumps always fit and larger dumps are
+// cut off after their headers.
+const maxDumpSize = 32 << 20
+
+var DumpCommand = tempest.Command{
+	Type:        tempest.CHAT_INPUT_COMMAND_TYPE,
+	Name:        "dump",
+	Description: "Analyze a crash dump",
+	Options: []tempest.CommandOption{
+		{
+			Type:        tempest.ATTACHMENT_OPTION_TYPE,
+			Name:        "file",
+			Description: "Minidump or kernel dump, e.g. from C:\\Windows\\Minidump",
+			Required:    true,
+		},
+	},
+	SlashCommandHandler: handleDump,
+}
+
+func handleDump(itx *tempest.CommandInteraction) {
+	attachment, ok := attachmentOption(itx, "file")
+
+	if !ok {
+		replyError(itx, "Attach a crash dump to analyze.")
+		return
+	}
+
+	// downloading the dump may take longer than Discord waits for a reply
+	if err := deferReply(itx); err != nil {
+		slog.Error("failed to defer reply", "command", itx.Data.Name, "error", err)
+		return
+	}
+
+	data, err := downloadAttachment(attachment.URL, maxDumpSize)
+
+	if err != nil {
+		slog.Error("failed to download attachment", "command", itx.Data.Name, "file", attachment.FileName, "error", err)
+		replyDeferredError(itx, "Could not download %s.", inlineCode(attachment.FileName))
+		return
+	}
+
+	crash, err := dump.Parse(bytes.NewReader(data))
+
+	if err != nil {
+		slog.Info("could not parse dump", "command", itx.Data.Name, "file", attachment.FileName, "error", err)
+		replyDeferredError(itx, "Could not read %s: %s.", inlineCode(attachment.FileName), describeDumpError(err))
+		return
+	}
+
+	reply(itx, tempest.ResponseMessageData{
+		Content: fmt.Sprintf("-# Read %s.", inlineCode(attachment.FileName)),
+		Embeds:  []tempest.Embed{createDumpEmbed(crash)},
+	})
+}
+
+func describeDumpError(err error) string {
+	switch {
+	case errors.Is(err, dump.ErrUnknownFormat):
+		return "it is not a minidump or a kernel dump. Small memory dumps are saved to `C:\\Windows\\Minidump` after a blue screen"
+	case errors.Is(err, dump.ErrTruncated):
+		return "the file ends too early, it may not have been saved completely"
+	case errors.Is(err, dump.ErrCorrupt):
+		return "the file is corrupted"
+	}
+
+	return err.Error()
+}
+
+func createDumpEmbed(crash *dump.Dump) tempest.Embed {
+	embed := tempest.Embed{}
+	modules := dump.NewModuleMap(crash.Modules)
+
+	if crash.HasBugCheck {
+		embed.Title = "Unknown bug check"
+
+		if matches := repoInstance.FindBugCheckCode(crash.BugCheckCode); len(matches) > 0 {
+			embed.Title = matches[0].Name
+			embed.Description = matches[0].Description
+			embed.URL = matches[0].URL
+		}
+
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  "Bugcheck code",
+			Value: fmt.Sprintf("`0x%08X`", crash.BugCheckCode),
+		})
+
+		for i, parameter := range repoInstance.DecodeBugCheck(crash.BugCheckCode, crash.BugCheckParameters) {
+			value := formatBugCheckParameter(parameter)
+
+			if address, ok := modules.Resolve(parameter.Value); ok {
+				value = fmt.Sprintf("%s\n> Address falls inside `%s`", value, address)
+			}
+
+			embed.Fields = append(embed.Fields, tempest.EmbedField{
+				Name:  fmt.Sprintf("Parameter %d", i+1),
+				Value: value,
+			})
+		}
+	} else {
+		embed.Title = "User-mode minidump"
+		embed.Description = "User-mode minidumps are written when an application crashes and do not record a bug check."
+	}
+
+	if crash.Exception != nil {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  "Exception",
+			Value: fmt.Sprintf("%s at %s", formatNTStatus(crash.Exception.Code), formatDumpAddress(modules, crash.Exception.Address)),
+		})
+	}
+
+	if address, source, ok := findFaultingModule(crash, modules); ok {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  "Faulting module",
+			Value: fmt.Sprintf("`%s`\nThe %s falls inside `%s`.", address.Module.BaseName(), source, address),
+		})
+	}
+
+	embed.Fields = append(embed.Fields, tempest.EmbedField{
+		Name:   "System",
+		Value:  describeDumpSystem(crash),
+		Inline: true,
+	})
+
+	if crash.HasBugCheck {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:   "Dump type",
+			Value:  fmt.Sprintf("%s (`%s`)", crash.DumpTypeName(), crash.Format),
+			Inline: true,
+		})
+	}
+
+	if len(crash.Modules) > 0 {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:   "Modules",
+			Value:  fmt.Sprintf("%d loaded", len(crash.Modules)),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+func describeDumpSystem(crash *dump.Dump) string {
+	lines := []string{}
+
+	if crash.MajorVersion != 0 {
+		lines = append(lines, fmt.Sprintf("Windows %d.%d build %d", crash.MajorVersion, crash.MinorVersion, crash.BuildNumber))
+	} else {
+		lines = append(lines, fmt.Sprintf("Windows build %d", crash.BuildNumber))
+	}
+
+	if crash.Architecture != "" {
+		lines = append(lines, crash.Architecture)
+	}
+
+	lines = append(lines, fmt.Sprintf("%d processors", crash.ProcessorCount))
+
+	return strings.Join(lines, "\n")
+}
+
+// findFaultingModule finds the module the crash most likely happened in by
+// matching the exception address, the instruction pointer and then the bug
+// check parameters against the module list. source says which address
+// matched.
+func findFaultingModule(crash *dump.Dump, modules dump.ModuleMap) (address dump.ModuleAddress, source string, ok bool) {
+	if crash.Exception != nil {
+		if address, ok := modules.Resolve(crash.Exception.Address); ok {
+			return address, "exception address", true
+		}
+
+		if address, ok := modules.Resolve(crash.Exception.InstructionPointer); ok {
+			return address, "instruction pointer", true
+		}
+	}
+
+	if crash.HasBugCheck {
+		for i, value := range crash.BugCheckParameters {
+			if address, ok := modules.Resolve(value); ok {
+				return address, fmt.Sprintf("address in parameter %d", i+1), true
+			}
+		}
+	}
+
+	return dump.ModuleAddress{}, "", false
+}
+
+func formatDumpAddress(modules dump.ModuleMap, value uint64) string {
+	if address, ok := modules.Resolve(value); ok {
+		return fmt.Sprintf("`%s`", address)
+	}
+
+	return fmt.Sprintf("`0x%X`", value)
+}
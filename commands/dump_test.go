@@ -0,0 +1,276 @@
+package commands
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/dhrdlicka/errorbot/dump"
+)
+
+// createKernelDump creates the header of a 64-bit small memory dump
+func createKernelDump(code uint32, parameters [4]uint64) []byte {
+	data := make([]byte, 0x2000)
+
+	copy(data, "PAGEDU64")
+	binary.LittleEndian.PutUint32(data[0x0C:], 19045)
+	binary.LittleEndian.PutUint32(data[0x30:], 0x8664)
+	binary.LittleEndian.PutUint32(data[0x34:], 8)
+	binary.LittleEndian.PutUint32(data[0x38:], code)
+
+	for i, parameter := range parameters {
+		binary.LittleEndian.PutUint64(data[0x40+i*8:], parameter)
+	}
+
+	binary.LittleEndian.PutUint32(data[0xF98:], 4)
+
+	return data
+}
+
+// createSmallMemoryDump creates a 64-bit small memory dump whose triage
+// header lists the drivers
+func createSmallMemoryDump(code uint32, parameters [4]uint64, drivers []dump.Module) []byte {
+	const entrySize = 0x90
+
+	data := append(createKernelDump(code, parameters), make([]byte, 0x38+len(drivers)*entrySize)...)
+	binary.LittleEndian.PutUint32(data[0x2008:], 0x40)
+	binary.LittleEndian.PutUint32(data[0x2030:], 0x2038)
+	binary.LittleEndian.PutUint32(data[0x2034:], uint32(len(drivers)))
+
+	for i, driver := range drivers {
+		entry := data[0x2038+i*entrySize:]
+
+		binary.LittleEndian.PutUint32(entry, uint32(len(data)))
+		binary.LittleEndian.PutUint64(entry[0x38:], driver.Base)
+		binary.LittleEndian.PutUint32(entry[0x48:], driver.Size)
+
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(driver.Name)))
+
+		for _, char := range driver.Name + "\x00" {
+			data = binary.LittleEndian.AppendUint16(data, uint16(char))
+		}
+	}
+
+	return data
+}
+
+// runDumpCommand runs /dump with a Mini.dmp attachment that downloads as
+// data, or fails to download if data is nil
+func runDumpCommand(t *testing.T, data []byte) []capturedReply {
+	t.Helper()
+
+	return runAttachmentCommand(t, handleDump, "dump", "Mini.dmp", data)
+}
+
+func TestHandleDump_KernelDump(t *testing.T) {
+	replies := runDumpCommand(t, createKernelDump(0x7E, [4]uint64{0xFFFFFFFFC0000005, 0xFFFFF8024B9B1960, 0, 0}))
+	response := expectEmbedReply(t, replies)
+	embed := response.Embeds[0]
+
+	if embed.Title != "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED" {
+		t.Errorf("title is %q", embed.Title)
+	}
+
+	if !strings.Contains(response.Content, "`Mini.dmp`") {
+		t.Errorf("content %q does not name the file", response.Content)
+	}
+
+	tests := []struct {
+		name     string
+		field    string
+		expected string
+	}{
+		{name: "code", field: "Bugcheck code", expected: "`0x0000007E`"},
+		{name: "exception code", field: "Parameter 1", expected: "STATUS_ACCESS_VIOLATION"},
+		{name: "address", field: "Parameter 2", expected: "`0xFFFFF8024B9B1960`"},
+		{name: "build", field: "System", expected: "Windows build 19045"},
+		{name: "architecture", field: "System", expected: "x64"},
+		{name: "dump type", field: "Dump type", expected: "Small memory dump (`PAGEDU64`)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, ok := fieldValue(embed, test.field)
+
+			if !ok {
+				t.Fatalf("embed has no %q field", test.field)
+			}
+
+			if !strings.Contains(value, test.expected) {
+				t.Errorf("field %q is %q, expected it to contain %q", test.field, value, test.expected)
+			}
+		})
+	}
+}
+
+func TestHandleDump_FaultingDriver(t *testing.T) {
+	drivers := []dump.Module{
+		{Name: "ntoskrnl.exe", Base: 0xFFFFF80012000000, Size: 0x1046000},
+		{Name: "nvlddmkm.sys", Base: 0xFFFFF80045600000, Size: 0x2F4C000},
+	}
+
+	replies := runDumpCommand(t, createSmallMemoryDump(0x7E, [4]uint64{0xFFFFFFFFC0000005, 0xFFFFF8004561A2B3, 0, 0}, drivers))
+	embed := expectEmbedReply(t, replies).Embeds[0]
+
+	tests := []struct {
+		name     string
+		field    string
+		expected string
+	}{
+		{name: "faulting module", field: "Faulting module", expected: "`nvlddmkm.sys`\nThe address in parameter 2 falls inside `nvlddmkm.sys+0x1A2B3`."},
+		{name: "resolved parameter", field: "Parameter 2", expected: "`0xFFFFF8004561A2B3`\n> Address falls inside `nvlddmkm.sys+0x1A2B3`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, ok := fieldValue(embed, test.field)
+
+			if !ok {
+				t.Fatalf("embed has no %q field", test.field)
+			}
+
+			if value != test.expected {
+				t.Errorf("field %q is %q, expected %q", test.field, value, test.expected)
+			}
+		})
+	}
+}
+
+func TestHandleDump_UnknownBugCheck(t *testing.T) {
+	replies := runDumpCommand(t, createKernelDump(0x1234, [4]uint64{1, 2, 3, 4}))
+	embed := expectEmbedReply(t, replies).Embeds[0]
+
+	if embed.Title != "Unknown bug check" {
+		t.Errorf("title is %q", embed.Title)
+	}
+
+	if value, _ := fieldValue(embed, "Parameter 4"); value != "`0x4`" {
+		t.Errorf("parameter 4 is %q", value)
+	}
+}
+
+func TestHandleDump_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "download failure", data: nil, expected: "Could not download `Mini.dmp`"},
+		{name: "not a dump", data: []byte("PK\x03\x04 this is a zip file"), expected: "not a minidump or a kernel dump"},
+		{name: "truncated", data: createKernelDump(0x7E, [4]uint64{})[:0x100], expected: "ends too early"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectErrorReply(t, runDumpCommand(t, test.data), test.expected)
+		})
+	}
+}
+
+func TestHandleDump_MissingAttachment(t *testing.T) {
+	replies := runCommand(t, handleDump, "dump", "file", "2")
+
+	expectErrorReply(t, replies, "Attach a crash dump")
+}
+
+var testDumpModules = []dump.Module{
+	{Name: `\SystemRoot\system32\ntoskrnl.exe`, Base: 0xFFFFF80012000000, Size: 0x1046000},
+	{Name: `\SystemRoot\System32\drivers\nvlddmkm.sys`, Base: 0xFFFFF80045600000, Size: 0x2F4C000},
+}
+
+func TestCreateDumpEmbed_FaultingModule(t *testing.T) {
+	oldRepo := repoInstance
+	t.Cleanup(func() {
+		repoInstance = oldRepo
+	})
+
+	repoInstance = createTestRepo()
+
+	tests := []struct {
+		name     string
+		crash    dump.Dump
+		field    string
+		expected string
+	}{
+		{
+			name: "bug check parameter",
+			crash: dump.Dump{
+				HasBugCheck:        true,
+				BugCheckCode:       0x7E,
+				BugCheckParameters: [4]uint64{0xFFFFFFFFC0000005, 0xFFFFF8004561A2B3, 0, 0},
+				Modules:            testDumpModules,
+			},
+			field:    "Faulting module",
+			expected: "`nvlddmkm.sys`\nThe address in parameter 2 falls inside `nvlddmkm.sys+0x1A2B3`.",
+		},
+		{
+			name: "resolved parameter",
+			crash: dump.Dump{
+				HasBugCheck:        true,
+				BugCheckCode:       0x7E,
+				BugCheckParameters: [4]uint64{0xFFFFFFFFC0000005, 0xFFFFF8004561A2B3, 0, 0},
+				Modules:            testDumpModules,
+			},
+			field:    "Parameter 2",
+			expected: "`0xFFFFF8004561A2B3`\n> Address falls inside `nvlddmkm.sys+0x1A2B3`",
+		},
+		{
+			name: "exception address",
+			crash: dump.Dump{
+				Format:    dump.FormatMinidump,
+				Modules:   testDumpModules,
+				Exception: &dump.Exception{Code: 0xC0000005, Address: 0xFFFFF80012001000},
+			},
+			field:    "Exception",
+			expected: "`STATUS_ACCESS_VIOLATION` (`0xC0000005`) at `ntoskrnl.exe+0x1000`",
+		},
+		{
+			name: "instruction pointer",
+			crash: dump.Dump{
+				Format:    dump.FormatMinidump,
+				Modules:   testDumpModules,
+				Exception: &dump.Exception{Code: 0x80000003, Address: 0x7FF612340000, InstructionPointer: 0xFFFFF80045600010},
+			},
+			field:    "Faulting module",
+			expected: "`nvlddmkm.sys`\nThe instruction pointer falls inside `nvlddmkm.sys+0x10`.",
+		},
+		{
+			name: "unresolved exception address",
+			crash: dump.Dump{
+				Format:    dump.FormatMinidump,
+				Exception: &dump.Exception{Code: 0x80000003, Address: 0x7FF612340000},
+			},
+			field:    "Exception",
+			expected: "`0x80000003` at `0x7FF612340000`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, ok := fieldValue(createDumpEmbed(&test.crash), test.field)
+
+			if !ok {
+				t.Fatalf("embed has no %q field", test.field)
+			}
+
+			if value != test.expected {
+				t.Errorf("field %q is %q, expected %q", test.field, value, test.expected)
+			}
+		})
+	}
+}
+
+func TestCreateDumpEmbed_NoModules(t *testing.T) {
+	oldRepo := repoInstance
+	t.Cleanup(func() {
+		repoInstance = oldRepo
+	})
+
+	repoInstance = createTestRepo()
+
+	embed := createDumpEmbed(&dump.Dump{HasBugCheck: true, BugCheckCode: 0x7E, BugCheckParameters: [4]uint64{0xC0000005, 0xFFFFF8004561A2B3}})
+
+	if value, ok := fieldValue(embed, "Faulting module"); ok {
+		t.Errorf("embed has a faulting module %q without a module list", value)
+	}
+}
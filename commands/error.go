@@ -2,12 +2,10 @@ package commands
 
 import (
 	"fmt"
-	"log/slog"
 	"strings"
 
 	tempest "github.com/amatsagu/tempest"
 	"github.com/dhrdlicka/errorbot/repo"
-	"github.com/dhrdlicka/errorbot/util"
 )
 
 var ErrorCommand = tempest.Command{
@@ -16,60 +14,76 @@ var ErrorCommand = tempest.Command{
 	Description: "Look up a Windows error code",
 	Options: []tempest.CommandOption{
 		{
-			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "code",
-			Description: "Error code",
-			Required:    true,
+			Type:         tempest.STRING_OPTION_TYPE,
+			Name:         "code",
+			Description:  "Error code",
+			Required:     true,
+			AutoComplete: true,
 		},
 	},
+	AutoCompleteHandler: autoCompleteCode(),
 	SlashCommandHandler: handleError,
 }
 
 func handleError(itx *tempest.CommandInteraction) {
-	value := itx.Data.Options[0].Value.(string)
-	codes, err := util.ParseCode(value)
+	value, _ := stringOption(itx, "code")
+	codes, reading, err := parseCode(value)
+
+	var response tempest.ResponseMessageData
+	var matches map[repo.Kind][]repo.ErrorInfo
 
 	if err != nil {
-		slog.Error("failed to parse command option", "error", err)
-		return
+		matches = findErrorsByName(value)
+	} else {
+		matches = findErrorsByCode(codes)
 	}
 
-	repos := []struct {
-		findCode func(uint32) []repo.ErrorInfo
-		name     string
-	}{
-		{repoInstance.FindBugCheck, "bug check"},
-		{repoInstance.FindHResult, "HRESULT"},
-		{repoInstance.FindWin32Error, "Win32 error"},
-		{repoInstance.FindNTStatus, "NTSTATUS"},
+	for _, kind := range repo.Kinds {
+		if len(matches[kind]) > 0 {
+			response.Embeds = append(response.Embeds, tempest.Embed{
+				Title:       fmt.Sprintf("Possible %s codes", kind),
+				Description: formatResults(matches[kind]),
+				Fields:      createMappingFields(kind, matches[kind]),
+			})
+		}
 	}
 
-	var response tempest.ResponseMessageData
+	if len(response.Embeds) == 0 {
+		if err != nil {
+			replyInvalidCode(itx, value, err)
+		} else {
+			replyError(itx, "Could not find error code %s (`0x%08X`)", value, codes[0])
+		}
 
-	found := false
+		return
+	}
 
-	for _, errorRepo := range repos {
-		matches := []repo.ErrorInfo{}
+	response.Content = reading
 
-		for _, code := range codes {
-			matches = append(matches, errorRepo.findCode(code)...)
-		}
+	reply(itx, response)
+}
 
-		if len(matches) > 0 {
-			found = true
+func findErrorsByCode(codes []uint32) map[repo.Kind][]repo.ErrorInfo {
+	matches := map[repo.Kind][]repo.ErrorInfo{}
 
-			response.Embeds = append(response.Embeds, tempest.Embed{
-				Title:       fmt.Sprintf("Possible %s codes", errorRepo.name),
-				Description: formatResults(matches),
-			})
+	for _, code := range codes {
+		for _, result := range repoInstance.Lookup(code) {
+			kind := result.Catalog.Kind()
+			matches[kind] = append(matches[kind], result.Matches...)
 		}
 	}
 
-	if !found {
-		response.Content = fmt.Sprintf("Could not find error code %s (`0x%08X`)", value, codes[0])
+	return matches
+}
+
+func findErrorsByName(name string, kinds ...repo.Kind) map[repo.Kind][]repo.ErrorInfo {
+	matches := map[repo.Kind][]repo.ErrorInfo{}
+
+	for _, match := range repoInstance.FindByName(name, kinds...) {
+		matches[match.Kind] = append(matches[match.Kind], match.ErrorInfo)
 	}
 
-	itx.SendReply(response, false, nil)
+	return matches
 }
 
 func formatResults(errors []repo.ErrorInfo) string {
@@ -81,7 +95,66 @@ func formatResults(errors []repo.ErrorInfo) string {
 		for _, line := range strings.Split(item.Description, "\n") {
 			result = fmt.Appendf(result, "> %s\n", strings.TrimSpace(line))
 		}
+
+		if availability := item.Availability(); availability != "" {
+			result = fmt.Appendf(result, "-# %s\n", availability)
+		}
 	}
 
 	return string(result)
 }
+
+// createAvailabilityFields says which SDK builds define the code, for codes
+// that are not defined by all of them.
+func createAvailabilityFields(item repo.ErrorInfo) []tempest.EmbedField {
+	availability := item.Availability()
+
+	if availability == "" {
+		return nil
+	}
+
+	return []tempest.EmbedField{{Name: "Availability", Value: availability}}
+}
+
+// createMappingFields lists the Win32 errors NTSTATUS results translate to
+// and the NTSTATUS codes that translate to Win32 error results.
+func createMappingFields(kind repo.Kind, errors []repo.ErrorInfo) []tempest.EmbedField {
+	var name string
+	var lines []string
+
+	switch kind {
+	case repo.KindNTStatus:
+		name = "Maps to"
+
+		for _, item := range errors {
+			if win32Error, ok := repoInstance.MapNTStatus(item.Code); ok {
+				lines = append(lines, fmt.Sprintf("`%s` → %s", item.Name, formatWin32Error(win32Error)))
+			}
+		}
+	case repo.KindWin32Error:
+		name = "Mapped from"
+
+		for _, item := range errors {
+			var statuses []string
+
+			for _, status := range repoInstance.MappedNTStatuses(item.Code) {
+				statuses = append(statuses, formatNTStatus(status))
+			}
+
+			if len(statuses) > 0 {
+				lines = append(lines, fmt.Sprintf("`%s` ← %s", item.Name, strings.Join(statuses, ", ")))
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return []tempest.EmbedField{
+		{
+			Name:  name,
+			Value: strings.Join(lines, "\n"),
+		},
+	}
+}
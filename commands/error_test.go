@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHandleError_MalformedInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "garbage", value: "not a code", fragments: []string{"`not a code`", "neither a number nor a known symbolic name", "0xC0000005"}},
+		{name: "overflow", value: "0x100000000", fragments: []string{"does not fit into a 32-bit code"}},
+		{name: "unknown name", value: "ERROR_DOES_NOT_EXIST", fragments: []string{"`ERROR_DOES_NOT_EXIST`", "STATUS_ACCESS_VIOLATION"}},
+		{name: "empty", value: "", fragments: []string{"No code was given"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleError, "error", "code", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleError_MissingOption(t *testing.T) {
+	replies := runCommand(t, handleError, "error")
+	expectErrorReply(t, replies, "No code was given")
+}
+
+func TestHandleError_NotFound(t *testing.T) {
+	replies := runCommand(t, handleError, "error", "code", "0xDEADBEEF")
+	expectErrorReply(t, replies, "Could not find error code 0xDEADBEEF (`0xDEADBEEF`)")
+}
+
+func TestHandleError_Found(t *testing.T) {
+	for _, value := range []string{"0xC0000005", "3221225477", "STATUS_ACCESS_VIOLATION", "status_access_violation"} {
+		t.Run(value, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleError, "error", "code", value))
+
+			if response.Embeds[0].Title != "Possible NTSTATUS codes" {
+				t.Errorf("first embed title = %q, expected %q", response.Embeds[0].Title, "Possible NTSTATUS codes")
+			}
+		})
+	}
+}
+
+func TestHandleError_Reading(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "plain hex", value: "0xC0000005", expected: ""},
+		{name: "name", value: "E_FAIL", expected: ""},
+		{name: "decimal", value: "3221225477", expected: "-# Read `3221225477` as decimal (`0xC0000005`)."},
+		{name: "WinDbg", value: "0xffffffff`c0000005", expected: "-# Read `` 0xffffffff`c0000005 `` as hexadecimal (WinDbg 64-bit value, sign-extended from 64 bits) (`0xC0000005`)."},
+		{name: "same value either way", value: "5", expected: ""},
+		{name: "ambiguous", value: "126", expected: "-# Read `126` as hexadecimal (`0x00000126`) or decimal (`0x0000007E`)."},
+		{name: "C suffix", value: "0x80004005L", expected: "-# Read `0x80004005L` as hexadecimal (C suffix L) (`0x80004005`)."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleError, "error", "code", tt.value))
+
+			if response.Content != tt.expected {
+				t.Errorf("content = %q, expected %q", response.Content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleError_ReadingWithBreakdown(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleHResult, "hresult", "code", "0n-2147467259"))
+
+	if !strings.Contains(response.Content, "WinDbg 0n prefix") {
+		t.Errorf("content = %q, expected the WinDbg reading", response.Content)
+	}
+}
+
+func TestHandleError_Macro(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		title   string
+		content string
+	}{
+		{name: "HRESULT_FROM_WIN32", value: "HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)", title: "Possible HRESULT codes", content: "-# Evaluated `HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)` to `0x80070005`."},
+		{name: "facility name", value: "MAKE_HRESULT(1, FACILITY_WIN32, 2)", title: "Possible HRESULT codes", content: "-# Evaluated `MAKE_HRESULT(1, FACILITY_WIN32, 2)` to `0x80070002`."},
+		{name: "HRESULT_CODE", value: "HRESULT_CODE(0x80070005)", title: "Possible Win32 error codes", content: "-# Evaluated `HRESULT_CODE(0x80070005)` to `0x00000005`."},
+		{name: "NTSTATUS_FROM_WIN32", value: "NTSTATUS_FROM_WIN32(2)", title: "Possible NTSTATUS codes", content: "-# Evaluated `NTSTATUS_FROM_WIN32(2)` to `0xC0070002`."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleError, "error", "code", tt.value))
+
+			titles := []string{}
+
+			for _, embed := range response.Embeds {
+				titles = append(titles, embed.Title)
+			}
+
+			if !strings.Contains(strings.Join(titles, "\n"), tt.title) {
+				t.Errorf("embed titles = %q, expected %q", titles, tt.title)
+			}
+
+			if response.Content != tt.content {
+				t.Errorf("content = %q, expected %q", response.Content, tt.content)
+			}
+		})
+	}
+}
+
+func TestHandleError_InvalidMacro(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "unknown name", value: "HRESULT_FROM_WIN32(ERROR_NOPE)", fragments: []string{"ERROR_NOPE is not a known symbolic name"}},
+		{name: "unknown macro", value: "FROBNICATE(5)", fragments: []string{"FROBNICATE is not a known macro"}},
+		{name: "wrong arity", value: "MAKE_HRESULT(1)", fragments: []string{"MAKE_HRESULT takes 3 arguments, not 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleError, "error", "code", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleError_Mappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		field    string
+		expected string
+	}{
+		{
+			name:     "NTSTATUS maps to Win32 error",
+			value:    "0xC0000022",
+			field:    "Maps to",
+			expected: "`STATUS_ACCESS_DENIED` → `ERROR_ACCESS_DENIED` (5)",
+		},
+		{
+			name:     "Win32 error mapped from NTSTATUS",
+			value:    "ERROR_ACCESS_DENIED",
+			field:    "Mapped from",
+			expected: "`ERROR_ACCESS_DENIED` ← `STATUS_ACCESS_DENIED` (`0xC0000022`)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleError, "error", "code", tt.value))
+			found := false
+
+			for _, embed := range response.Embeds {
+				if value, ok := fieldValue(embed, tt.field); ok {
+					found = true
+
+					if value != tt.expected {
+						t.Errorf("%s = %q, expected %q", tt.field, value, tt.expected)
+					}
+				}
+			}
+
+			if !found {
+				t.Errorf("no embed has a %s field", tt.field)
+			}
+		})
+	}
+}
+
+func TestHandleError_AllCatalogs(t *testing.T) {
+	// Windows Update Agent codes come from a catalog of their own and are
+	// reported with the other HRESULTs
+	response := expectEmbedReply(t, runCommand(t, handleError, "error", "code", "0x8024402C"))
+	embed := response.Embeds[0]
+
+	if embed.Title != "Possible HRESULT codes" || !strings.Contains(embed.Description, "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED") {
+		t.Errorf("embed = %q: %q, expected WU_E_PT_WINHTTP_NAME_NOT_RESOLVED among the HRESULT codes", embed.Title, embed.Description)
+	}
+}
+
+func TestHandleError_Availability(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleError, "error", "code", "E_ILLEGAL_DELEGATE_ASSIGNMENT"))
+
+	if description := response.Embeds[0].Description; !strings.Contains(description, "\n-# Available since 10.0.19041") {
+		t.Errorf("description = %q, expected the availability", description)
+	}
+}
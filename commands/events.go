@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/eventlog"
+)
+
+// maxEventsSize limits how much of an event export is downloaded
+const maxEventsSize = 8 << 20
+
+var EventsCommand = tempest.Command{
+	Type:        tempest.CHAT_INPUT_COMMAND_TYPE,
+	Name:        "events",
+	Description: "Find error codes in events exported from the event log",
+	Options: []tempest.CommandOption{
+		{
+			Type:        tempest.ATTACHMENT_OPTION_TYPE,
+			Name:        "file",
+			Description: "Events saved as XML from Event Viewer or with wevtutil qe /f:xml",
+			Required:    true,
+		},
+	},
+	SlashCommandHandler: handleEvents,
+}
+
+func handleEvents(itx *tempest.CommandInteraction) {
+	attachment, ok := attachmentOption(itx, "file")
+
+	if !ok {
+		replyError(itx, "Attach events saved as XML to analyze.")
+		return
+	}
+
+	if err := deferReply(itx); err != nil {
+		slog.Error("failed to defer reply", "command", itx.Data.Name, "error", err)
+		return
+	}
+
+	data, err := downloadAttachment(attachment.URL, maxEventsSize)
+
+	if err != nil {
+		slog.Error("failed to download attachment", "command", itx.Data.Name, "file", attachment.FileName, "error", err)
+		replyDeferredError(itx, "Could not download %s.", inlineCode(attachment.FileName))
+		return
+	}
+
+	events, err := eventlog.Parse(bytes.NewReader(data))
+
+	if len(events) == 0 {
+		slog.Info("could not parse events", "command", itx.Data.Name, "file", attachment.FileName, "error", err)
+		replyDeferredError(itx, "Could not read events from %s: %s.", inlineCode(attachment.FileName), describeEventsError(err))
+		return
+	}
+
+	embeds := []tempest.Embed{}
+
+	for _, event := range events {
+		if report := eventlog.Analyze(repoInstance, event); report.HasCodes() {
+			embeds = append(embeds, createEventEmbed(report))
+		}
+	}
+
+	if len(embeds) == 0 {
+		replyDeferredError(itx, "Could not find any error codes in the events in %s.", inlineCode(attachment.FileName))
+		return
+	}
+
+	content := fmt.Sprintf("-# Found error codes in %d of %d events in %s.", len(embeds), len(events), inlineCode(attachment.FileName))
+
+	// the events read before the file got cut off are still worth a look
+	if err != nil {
+		content += "\n-# The rest of the file could not be read."
+	}
+
+	replyPages(itx, 0, content, embeds)
+}
+
+func describeEventsError(err error) string {
+	if errors.Is(err, eventlog.ErrNoEvents) {
+		return "the file does not contain any events. In Event Viewer, select the events and use **Save Selected Events** with the XML file type"
+	}
+
+	return "the file is not valid XML"
+}
+
+func createEventEmbed(report eventlog.Report) tempest.Embed {
+	event := report.Event
+
+	details := []string{event.LevelName()}
+
+	if !event.TimeCreated.IsZero() {
+		details = append(details, fmt.Sprintf("<t:%d:f>", event.TimeCreated.Unix()))
+	}
+
+	if event.Channel != "" {
+		details = append(details, event.Channel)
+	}
+
+	embed := tempest.Embed{
+		Title:       fmt.Sprintf("Event %d from %s", event.EventID, event.Provider),
+		Description: strings.Join(details, " · "),
+	}
+
+	if bugCheck := report.BugCheck; bugCheck != nil {
+		name := bugCheck.Name
+
+		if name == "" {
+			name = "Unknown bug check"
+		}
+
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  "Bug check",
+			Value: fmt.Sprintf("`%s` (`0x%08X`)", name, bugCheck.Code),
+		})
+
+		for i, parameter := range bugCheck.Parameters {
+			embed.Fields = append(embed.Fields, tempest.EmbedField{
+				Name:  fmt.Sprintf("Parameter %d", i+1),
+				Value: formatBugCheckParameter(parameter),
+			})
+		}
+	}
+
+	for _, finding := range report.Findings {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  finding.Field,
+			Value: formatFinding(finding),
+		})
+	}
+
+	return embed
+}
+
+func formatFinding(finding eventlog.Finding) string {
+	lines := []string{fmt.Sprintf("%s (`0x%08X`)", inlineCode(finding.Text), finding.Code)}
+
+	if len(finding.Matches) == 0 {
+		lines = append(lines, "Not a known error code")
+	}
+
+	for _, match := range finding.Matches {
+		lines = append(lines, fmt.Sprintf("`%s` (%s)", match.Name, match.Kind))
+
+		if description := firstLine(match.Description); description != "" {
+			lines = append(lines, "> "+description)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+const testEvents = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<Events>
+<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Kernel-Power"/>
+    <EventID>41</EventID>
+    <Level>1</Level>
+    <TimeCreated SystemTime="2024-03-05T21:14:07.4915320Z"/>
+    <Channel>System</Channel>
+  </System>
+  <EventData>
+    <Data Name="BugcheckCode">126</Data>
+    <Data Name="BugcheckParameter1">0xffffffffc0000005</Data>
+    <Data Name="BugcheckParameter2">0xfffff8024b9b1960</Data>
+    <Data Name="BugcheckParameter3">0x0</Data>
+    <Data Name="BugcheckParameter4">0x0</Data>
+  </EventData>
+</Event>
+<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Kernel-General"/>
+    <EventID>12</EventID>
+    <Level>4</Level>
+    <Channel>System</Channel>
+  </System>
+  <EventData>
+    <Data Name="MajorVersion">10</Data>
+  </EventData>
+</Event>
+<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Service Control Manager"/>
+    <EventID Qualifiers="49152">7000</EventID>
+    <Level>2</Level>
+    <Channel>System</Channel>
+  </System>
+  <EventData>
+    <Data Name="param1">Contoso Updater</Data>
+    <Data Name="param2">%%5</Data>
+  </EventData>
+</Event>
+</Events>`
+
+func TestHandleEvents(t *testing.T) {
+	replies := runAttachmentCommand(t, handleEvents, "events", "System.xml", []byte(testEvents))
+	response := expectEmbedReply(t, replies)
+
+	if !strings.Contains(response.Content, "Found error codes in 2 of 3 events in `System.xml`") {
+		t.Errorf("content is %q", response.Content)
+	}
+
+	if len(response.Embeds) != 2 {
+		t.Fatalf("reply has %d embeds, expected 2", len(response.Embeds))
+	}
+
+	tests := []struct {
+		name     string
+		embed    int
+		field    string
+		expected string
+	}{
+		{name: "bug check", embed: 0, field: "Bug check", expected: "`SYSTEM_THREAD_EXCEPTION_NOT_HANDLED` (`0x0000007E`)"},
+		{name: "bug check parameter", embed: 0, field: "Parameter 1", expected: "STATUS_ACCESS_VIOLATION"},
+		{name: "insertion string", embed: 1, field: "param2", expected: "`ERROR_ACCESS_DENIED` (Win32 error)\n> Access is denied."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, ok := fieldValue(response.Embeds[test.embed], test.field)
+
+			if !ok {
+				t.Fatalf("embed %d has no %q field", test.embed, test.field)
+			}
+
+			if !strings.Contains(value, test.expected) {
+				t.Errorf("field %q is %q, expected it to contain %q", test.field, value, test.expected)
+			}
+		})
+	}
+
+	if title := response.Embeds[0].Title; title != "Event 41 from Microsoft-Windows-Kernel-Power" {
+		t.Errorf("title is %q", title)
+	}
+
+	if description := response.Embeds[0].Description; description != "Critical · <t:1709673247:f> · System" {
+		t.Errorf("description is %q", description)
+	}
+}
+
+func TestHandleEvents_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "download failure", data: nil, expected: "Could not download `System.xml`"},
+		{name: "not XML", data: []byte("Level\tDate and Time\tSource\nError\t3/5/2024\tService Control Manager"), expected: "does not contain any events"},
+		{name: "broken XML", data: []byte("<Events><Event><System></Event>"), expected: "not valid XML"},
+		{name: "no codes", data: []byte(`<Event><System><EventID>12</EventID></System><EventData><Data Name="MajorVersion">10</Data></EventData></Event>`), expected: "Could not find any error codes in the events in `System.xml`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectErrorReply(t, runAttachmentCommand(t, handleEvents, "events", "System.xml", test.data), test.expected)
+		})
+	}
+}
+
+func TestHandleEvents_MissingAttachment(t *testing.T) {
+	expectErrorReply(t, runCommand(t, handleEvents, "events", "file", "2"), "Attach events")
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+// maxSummaryLength limits the description shown next to each explained code
+const maxSummaryLength = 150
+
+var ExplainCommand = tempest.Command{
+	Type:                tempest.MESSAGE_COMMAND_TYPE,
+	Name:                "Explain error codes",
+	SlashCommandHandler: handleExplain,
+}
+
+func handleExplain(itx *tempest.CommandInteraction) {
+	if itx.Data.Resolved == nil {
+		replyError(itx, "Could not read the message.")
+		return
+	}
+
+	message, ok := itx.Data.Resolved.Messages[itx.Data.TargetID]
+
+	if !ok {
+		replyError(itx, "Could not read the message.")
+		return
+	}
+
+	description := explainCodes(util.ExtractCodes(messageText(message)))
+
+	if description == "" {
+		replyError(itx, "Could not find any known error codes in this message.")
+		return
+	}
+
+	reply(itx, tempest.ResponseMessageData{
+		Embeds: []tempest.Embed{
+			{
+				Title:       "Error codes in the message",
+				Description: description,
+			},
+		},
+	})
+}
+
+// messageText returns the text of a message including its embeds, which is
+// where other bots put their output.
+func messageText(message tempest.Message) string {
+	parts := []string{message.Content}
+
+	for _, embed := range message.Embeds {
+		parts = append(parts, embed.Title, embed.Description)
+
+		for _, field := range embed.Fields {
+			parts = append(parts, field.Name, field.Value)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// explainCodes resolves the extracted codes and summarizes each one that is
+// known on a few lines. Codes that match nothing are left out.
+func explainCodes(matches []util.CodeMatch) string {
+	var result []byte
+
+	for _, match := range matches {
+		var found map[repo.Kind][]repo.ErrorInfo
+
+		if match.IsName() {
+			found = findExactName(match.Text)
+		} else {
+			found = findErrorsByCode(match.Codes)
+		}
+
+		var lines []byte
+
+		for _, kind := range likelyKinds(match.Kinds, found) {
+			for _, item := range found[kind] {
+				lines = fmt.Appendf(lines, "`%s` (`0x%08X`, %s)\n", item.Name, item.Code, kind)
+
+				if summary := firstLine(item.Description); summary != "" {
+					lines = fmt.Appendf(lines, "> %s\n", truncate(summary, maxSummaryLength))
+				}
+			}
+		}
+
+		if len(lines) > 0 {
+			result = fmt.Appendf(result, "**%s**\n%s\n", match.Text, lines)
+		}
+	}
+
+	return strings.TrimSpace(string(result))
+}
+
+// likelyKinds returns the catalogs to show results from. When the context
+// hints at catalogs that have results, the others are left out, so that
+// "STOP 0x7E" does not show Win32 error 126 as well.
+func likelyKinds(hints []repo.Kind, found map[repo.Kind][]repo.ErrorInfo) []repo.Kind {
+	for _, kind := range hints {
+		if len(found[kind]) > 0 {
+			return hints
+		}
+	}
+
+	return repo.Kinds
+}
+
+// findExactName looks up a symbolic name found in free text. Unlike the
+// commands, prefix matches are not accepted as the name was not typed on
+// purpose.
+func findExactName(name string) map[repo.Kind][]repo.ErrorInfo {
+	matches := map[repo.Kind][]repo.ErrorInfo{}
+
+	for _, match := range repoInstance.FindByName(name) {
+		if strings.EqualFold(match.Name, name) {
+			matches[match.Kind] = append(matches[match.Kind], match.ErrorInfo)
+		}
+	}
+
+	return matches
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
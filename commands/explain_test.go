@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+// explainMessage runs the message command on a message with the given content
+func explainMessage(t *testing.T, message tempest.Message) []capturedReply {
+	t.Helper()
+
+	handler := func(itx *tempest.CommandInteraction) {
+		itx.Data.TargetID = 1
+		itx.Data.Resolved = &tempest.InteractionDataResolved{
+			Messages: map[tempest.Snowflake]tempest.Message{1: message},
+		}
+
+		handleExplain(itx)
+	}
+
+	return runCommand(t, handler, "Explain error codes")
+}
+
+func TestHandleExplain(t *testing.T) {
+	message := tempest.Message{
+		Content: "Setup failed with 0x80004005, then the service died with 3221225477.\n" +
+			"The log also says ERROR_FILE_NOT_FOUND and 0xDEADBEEF.",
+	}
+
+	response := expectEmbedReply(t, explainMessage(t, message))
+	description := response.Embeds[0].Description
+
+	for _, fragment := range []string{"**0x80004005**", "`E_FAIL`", "**3221225477**", "`STATUS_ACCESS_VIOLATION`", "**ERROR_FILE_NOT_FOUND**", "> The system cannot find the file specified."} {
+		if !strings.Contains(description, fragment) {
+			t.Errorf("description does not contain %q:\n%s", fragment, description)
+		}
+	}
+
+	if strings.Contains(description, "DEADBEEF") {
+		t.Errorf("description lists an unknown code:\n%s", description)
+	}
+}
+
+func TestHandleExplain_Embeds(t *testing.T) {
+	message := tempest.Message{
+		Embeds: []tempest.Embed{
+			{Fields: []tempest.EmbedField{{Name: "Exit code", Value: "0xC0000022"}}},
+		},
+	}
+
+	response := expectEmbedReply(t, explainMessage(t, message))
+
+	if !strings.Contains(response.Embeds[0].Description, "`STATUS_ACCESS_DENIED`") {
+		t.Errorf("description = %q, expected STATUS_ACCESS_DENIED", response.Embeds[0].Description)
+	}
+}
+
+func TestHandleExplain_NothingFound(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty", content: ""},
+		{name: "no codes", content: "my computer keeps crashing, please help"},
+		{name: "unknown codes", content: "0xDEADBEEF and STATUS_NOT_A_THING"},
+		{name: "name prefix", content: "STATUS_ACCESS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := explainMessage(t, tempest.Message{Content: tt.content})
+			expectErrorReply(t, replies, "Could not find any known error codes")
+		})
+	}
+}
+
+func TestHandleExplain_Unresolved(t *testing.T) {
+	replies := runCommand(t, handleExplain, "Explain error codes")
+	expectErrorReply(t, replies, "Could not read the message.")
+}
+
+func TestHandleExplain_Hints(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		included []string
+	}{
+		{name: "bug check", content: "STOP 0x0000007E", included: []string{"SYSTEM_THREAD_EXCEPTION_NOT_HANDLED"}},
+		{name: "exit code", content: "exit code 5", included: []string{"ERROR_ACCESS_DENIED"}},
+		{name: "hint without results", content: "HRESULT 0xC0000005", included: []string{"STATUS_ACCESS_VIOLATION"}},
+		{name: "win32 hint", content: "GetLastError() = 2", included: []string{"ERROR_FILE_NOT_FOUND"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, explainMessage(t, tempest.Message{Content: tt.content}))
+
+			for _, fragment := range tt.included {
+				if !strings.Contains(response.Embeds[0].Description, fragment) {
+					t.Errorf("description does not contain %q:\n%s", fragment, response.Embeds[0].Description)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package commands
 
 import (
 	"fmt"
-	"log/slog"
+	"slices"
 
 	tempest "github.com/amatsagu/tempest"
 	"github.com/dhrdlicka/errorbot/repo"
@@ -16,28 +16,29 @@ var HResultCommand = tempest.Command{
 	Description: "Look up a HRESULT error code",
 	Options: []tempest.CommandOption{
 		{
-			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "code",
-			Description: "HRESULT code",
-			Required:    true,
+			Type:         tempest.STRING_OPTION_TYPE,
+			Name:         "code",
+			Description:  "HRESULT code",
+			Required:     true,
+			AutoComplete: true,
 		},
 	},
+	AutoCompleteHandler: autoCompleteCode(repo.KindHResult),
 	SlashCommandHandler: handleHResult,
 }
 
 func handleHResult(itx *tempest.CommandInteraction) {
-	value := itx.Data.Options[0].Value.(string)
-	codes, err := util.ParseCode(value)
-
-	if err != nil {
-		slog.Error("failed to parse command option", "error", err)
-		return
-	}
+	value, _ := stringOption(itx, "code")
+	codes, reading, err := parseCode(value)
 
 	matches := []repo.ErrorInfo{}
 
-	for _, code := range codes {
-		matches = append(matches, repoInstance.FindHResult(code)...)
+	if err != nil {
+		matches = findErrorsByName(value, repo.KindHResult)[repo.KindHResult]
+	} else {
+		for _, code := range codes {
+			matches = append(matches, repoInstance.FindHResult(code)...)
+		}
 	}
 
 	var response tempest.ResponseMessageData
@@ -46,12 +47,17 @@ func handleHResult(itx *tempest.CommandInteraction) {
 		for _, match := range matches {
 			response.Embeds = append(response.Embeds, createHResultEmbed(match))
 		}
+	} else if err != nil {
+		replyInvalidCode(itx, value, err)
+		return
 	} else {
 		// only break down the hexadecimal code if possible
 		response.Embeds = append(response.Embeds, createUnknownHResultEmbed(codes[0]))
 	}
 
-	itx.SendReply(response, false, nil)
+	response.Content = reading
+
+	reply(itx, response)
 }
 
 func hResultSeverityToString(severity bool) string {
@@ -66,13 +72,16 @@ func createHResultEmbed(hResult repo.ErrorInfo) tempest.Embed {
 	return tempest.Embed{
 		Title:       hResult.Name,
 		Description: hResult.Description,
-		Fields: append(
+		Fields: slices.Concat(
 			[]tempest.EmbedField{
 				{
 					Name:  "HRESULT code",
 					Value: fmt.Sprintf("`0x%08X` (%d)", hResult.Code, hResult.Code),
 				},
-			}, createHResultEmbedFields(winerror.HResult(hResult.Code))...),
+			},
+			createHResultEmbedFields(winerror.HResult(hResult.Code)),
+			createAvailabilityFields(hResult),
+		),
 	}
 }
 
@@ -96,11 +105,11 @@ func createHResultEmbedFields(hResult winerror.HResult) []tempest.EmbedField {
 
 	facility := fmt.Sprintf("%d", hResult.Facility())
 
-	if facility_name, ok := repoInstance.HResult.Facilities[hResult.Facility()]; ok {
+	if facility_name, ok := repoInstance.FacilityName(repo.KindHResult, hResult.Facility()); ok {
 		facility = fmt.Sprintf("%s (%s)", facility_name, facility)
 	}
 
-	return []tempest.EmbedField{
+	fields := []tempest.EmbedField{
 		{
 			Name:   "Severity",
 			Value:  fmt.Sprintf("%s (%d)", hResultSeverityToString(hResult.S()), util.BoolToInt(hResult.S())),
@@ -137,4 +146,14 @@ func createHResultEmbedFields(hResult winerror.HResult) []tempest.EmbedField {
 			Inline: true,
 		},
 	}
+
+	if component, ok := repo.WindowsUpdateComponent(uint32(hResult)); ok {
+		fields = append(fields, tempest.EmbedField{
+			Name:   "Windows Update component",
+			Value:  component,
+			Inline: true,
+		})
+	}
+
+	return fields
 }
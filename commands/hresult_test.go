@@ -0,0 +1,97 @@
+package commands
+
+import "testing"
+
+func TestHandleHResult_MalformedInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "garbage", value: "0xZZZ", fragments: []string{"`0xZZZ`", "neither a number nor a known symbolic name"}},
+		{name: "unknown name", value: "E_NOT_A_THING", fragments: []string{"`E_NOT_A_THING`", "E_FAIL"}},
+		{name: "name from another catalog", value: "STATUS_ACCESS_VIOLATION", fragments: []string{"`STATUS_ACCESS_VIOLATION`"}},
+		{name: "empty", value: "", fragments: []string{"No code was given"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleHResult, "hresult", "code", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleHResult_Found(t *testing.T) {
+	for _, value := range []string{"0x80004005", "-2147467259", "e_fail", "0x80070005"} {
+		t.Run(value, func(t *testing.T) {
+			expectEmbedReply(t, runCommand(t, handleHResult, "hresult", "code", value))
+		})
+	}
+}
+
+func TestHandleHResult_UnknownCode(t *testing.T) {
+	// valid codes that are not in the catalog are still broken down
+	response := expectEmbedReply(t, runCommand(t, handleHResult, "hresult", "code", "0x8BADF00D"))
+
+	if response.Embeds[0].Title != "" {
+		t.Errorf("embed title = %q, expected none", response.Embeds[0].Title)
+	}
+}
+
+func TestHandleHResult_WindowsUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		title    string
+		expected string
+	}{
+		{name: "by code", value: "0x8024402C", title: "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", expected: "Protocol talker"},
+		{name: "by name", value: "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", title: "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", expected: "Protocol talker"},
+		{name: "unknown code", value: "0x8024DEAD", expected: "Setup and self-update"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleHResult, "hresult", "code", tt.value))
+			embed := response.Embeds[0]
+
+			if embed.Title != tt.title {
+				t.Errorf("embed title = %q, expected %q", embed.Title, tt.title)
+			}
+
+			if component, _ := fieldValue(embed, "Windows Update component"); component != tt.expected {
+				t.Errorf("Windows Update component = %q, expected %q", component, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandleHResult_NoWindowsUpdateComponent(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleHResult, "hresult", "code", "0x80004005"))
+
+	if component, ok := fieldValue(response.Embeds[0], "Windows Update component"); ok {
+		t.Errorf("Windows Update component = %q, expected none", component)
+	}
+}
+
+func TestHandleHResult_Availability(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "introduced", value: "0x80000018", expected: "Available since 10.0.19041"},
+		{name: "always defined", value: "E_FAIL", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleHResult, "hresult", "code", tt.value))
+
+			if availability, _ := fieldValue(response.Embeds[0], "Availability"); availability != tt.expected {
+				t.Errorf("Availability = %q, expected %q", availability, tt.expected)
+			}
+		})
+	}
+}
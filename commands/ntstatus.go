@@ -2,7 +2,7 @@ package commands
 
 import (
 	"fmt"
-	"log/slog"
+	"slices"
 
 	tempest "github.com/amatsagu/tempest"
 	"github.com/dhrdlicka/errorbot/repo"
@@ -16,28 +16,29 @@ var NTStatusCommand = tempest.Command{
 	Description: "Look up an NTSTATUS error code",
 	Options: []tempest.CommandOption{
 		{
-			Type:        tempest.STRING_OPTION_TYPE,
-			Name:        "code",
-			Description: "NTSTATUS code",
-			Required:    true,
+			Type:         tempest.STRING_OPTION_TYPE,
+			Name:         "code",
+			Description:  "NTSTATUS code",
+			Required:     true,
+			AutoComplete: true,
 		},
 	},
+	AutoCompleteHandler: autoCompleteCode(repo.KindNTStatus),
 	SlashCommandHandler: handleNTStatus,
 }
 
 func handleNTStatus(itx *tempest.CommandInteraction) {
-	value := itx.Data.Options[0].Value.(string)
-	codes, err := util.ParseCode(value)
-
-	if err != nil {
-		slog.Error("failed to parse command option", "error", err)
-		return
-	}
+	value, _ := stringOption(itx, "code")
+	codes, reading, err := parseCode(value)
 
 	matches := []repo.ErrorInfo{}
 
-	for _, code := range codes {
-		matches = append(matches, repoInstance.FindNTStatus(code)...)
+	if err != nil {
+		matches = findErrorsByName(value, repo.KindNTStatus)[repo.KindNTStatus]
+	} else {
+		for _, code := range codes {
+			matches = append(matches, repoInstance.FindNTStatus(code)...)
+		}
 	}
 
 	var response tempest.ResponseMessageData
@@ -46,12 +47,17 @@ func handleNTStatus(itx *tempest.CommandInteraction) {
 		for _, match := range matches {
 			response.Embeds = append(response.Embeds, createNTStatusEmbed(match))
 		}
+	} else if err != nil {
+		replyInvalidCode(itx, value, err)
+		return
 	} else {
 		// only break down the hexadecimal code if possible
 		response.Embeds = append(response.Embeds, createUnknownNTStatusEmbed(codes[0]))
 	}
 
-	itx.SendReply(response, false, nil)
+	response.Content = reading
+
+	reply(itx, response)
 }
 
 func ntStatusSeverityToString(severity uint8) string {
@@ -73,32 +79,39 @@ func createNTStatusEmbed(ntStatus repo.ErrorInfo) tempest.Embed {
 	return tempest.Embed{
 		Title:       ntStatus.Name,
 		Description: ntStatus.Description,
-		Fields: append(
+		Fields: slices.Concat(
 			[]tempest.EmbedField{
 				{
 					Name:  "NTSTATUS code",
 					Value: fmt.Sprintf("`0x%08X` (%d)", ntStatus.Code, ntStatus.Code),
 				},
-			}, createNTStatusEmbedFields(winerror.NTStatus(ntStatus.Code))...),
+			},
+			createNTStatusEmbedFields(winerror.NTStatus(ntStatus.Code)),
+			createNTStatusMappingFields(ntStatus.Code),
+			createAvailabilityFields(ntStatus),
+		),
 	}
 }
 
 func createUnknownNTStatusEmbed(code uint32) tempest.Embed {
 	return tempest.Embed{
-		Fields: append(
+		Fields: slices.Concat(
 			[]tempest.EmbedField{
 				{
 					Name:  "NTSTATUS code",
 					Value: fmt.Sprintf("`0x%08X` (%d)", code, code),
 				},
-			}, createNTStatusEmbedFields(winerror.NTStatus(code))...),
+			},
+			createNTStatusEmbedFields(winerror.NTStatus(code)),
+			createNTStatusMappingFields(code),
+		),
 	}
 }
 
 func createNTStatusEmbedFields(status winerror.NTStatus) []tempest.EmbedField {
 	facility := fmt.Sprintf("%d", status.Facility())
 
-	if facility_name, ok := repoInstance.NTStatus.Facilities[status.Facility()]; ok {
+	if facility_name, ok := repoInstance.FacilityName(repo.KindNTStatus, status.Facility()); ok {
 		facility = fmt.Sprintf("%s (%s)", facility_name, facility)
 	}
 
@@ -130,3 +143,36 @@ func createNTStatusEmbedFields(status winerror.NTStatus) []tempest.EmbedField {
 		},
 	}
 }
+
+func createNTStatusMappingFields(code uint32) []tempest.EmbedField {
+	win32Error, ok := repoInstance.MapNTStatus(code)
+
+	if !ok {
+		return nil
+	}
+
+	return []tempest.EmbedField{
+		{
+			Name:  "Maps to",
+			Value: formatWin32Error(win32Error),
+		},
+	}
+}
+
+// formatWin32Error shows a Win32 error code with its name if it is known
+func formatWin32Error(code uint32) string {
+	if matches := repoInstance.FindWin32Error(code); len(matches) > 0 {
+		return fmt.Sprintf("`%s` (%d)", matches[0].Name, code)
+	}
+
+	return fmt.Sprintf("%d", code)
+}
+
+// formatNTStatus shows an NTSTATUS code with its name if it is known
+func formatNTStatus(code uint32) string {
+	if matches := repoInstance.FindNTStatus(code); len(matches) > 0 {
+		return fmt.Sprintf("`%s` (`0x%08X`)", matches[0].Name, code)
+	}
+
+	return fmt.Sprintf("`0x%08X`", code)
+}
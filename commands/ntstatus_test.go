@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"testing"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+func TestHandleNTStatus_MalformedInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		fragments []string
+	}{
+		{name: "garbage", value: "STATUS?", fragments: []string{"`STATUS?`"}},
+		{name: "unknown name", value: "STATUS_NOT_A_THING", fragments: []string{"`STATUS_NOT_A_THING`", "STATUS_ACCESS_VIOLATION"}},
+		{name: "overflow", value: "99999999999", fragments: []string{"does not fit into a 32-bit code"}},
+		{name: "empty", value: "", fragments: []string{"No code was given"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleNTStatus, "ntstatus", "code", tt.value)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleNTStatus_Found(t *testing.T) {
+	for _, value := range []string{"0xC0000005", "C0000005", "STATUS_ACCESS_VIOLATION", "0xC0070005"} {
+		t.Run(value, func(t *testing.T) {
+			expectEmbedReply(t, runCommand(t, handleNTStatus, "ntstatus", "code", value))
+		})
+	}
+}
+
+// fieldValue returns the value of the embed field with the given name
+func fieldValue(embed tempest.Embed, name string) (string, bool) {
+	for _, field := range embed.Fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func TestHandleNTStatus_MapsTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "mapping table", value: "STATUS_ACCESS_DENIED", expected: "`ERROR_ACCESS_DENIED` (5)"},
+		{name: "NTSTATUS_FROM_WIN32", value: "0xC0070002", expected: "`ERROR_FILE_NOT_FOUND` (2)"},
+		{name: "unknown Win32 error", value: "0xC0070063", expected: "99"},
+		{name: "not mapped", value: "0xC0000005", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := expectEmbedReply(t, runCommand(t, handleNTStatus, "ntstatus", "code", tt.value))
+			value, _ := fieldValue(response.Embeds[0], "Maps to")
+
+			if value != tt.expected {
+				t.Errorf("Maps to = %q, expected %q", value, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+const (
+	pagerPrefix = "page:"
+
+	// interaction tokens expire after 15 minutes, so there is no point in
+	// keeping the pages around for longer
+	pagerLifetime = 15 * time.Minute
+)
+
+type pagedReply struct {
+	pages   []tempest.ResponseMessageData
+	expires time.Time
+}
+
+var pager = struct {
+	sync.Mutex
+	replies map[string]pagedReply
+}{
+	replies: map[string]pagedReply{},
+}
+
+// updateMessage replaces the message a component is attached to. Tests
+// replace it to capture the updates without talking to Discord.
+var updateMessage = func(itx *tempest.ComponentInteraction, response tempest.ResponseMessageData) error {
+	_, err := itx.BaseClient.Rest.Request(http.MethodPatch, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token+"/messages/@original", response)
+	return err
+}
+
+// storePages keeps the pages of a reply so that the pager buttons can switch
+// between them, and returns the key identifying them.
+func storePages(pages []tempest.ResponseMessageData) string {
+	buffer := make([]byte, 8)
+	rand.Read(buffer)
+	key := hex.EncodeToString(buffer)
+
+	now := time.Now()
+
+	pager.Lock()
+	defer pager.Unlock()
+
+	for key, reply := range pager.replies {
+		if now.After(reply.expires) {
+			delete(pager.replies, key)
+		}
+	}
+
+	pager.replies[key] = pagedReply{
+		pages:   pages,
+		expires: now.Add(pagerLifetime),
+	}
+
+	return key
+}
+
+func loadPages(key string) ([]tempest.ResponseMessageData, bool) {
+	pager.Lock()
+	defer pager.Unlock()
+
+	reply, ok := pager.replies[key]
+
+	if !ok || time.Now().After(reply.expires) {
+		return nil, false
+	}
+
+	return reply.pages, true
+}
+
+// pageWithButtons returns the page at index with prev/next buttons attached.
+func pageWithButtons(key string, pages []tempest.ResponseMessageData, index int) tempest.ResponseMessageData {
+	page := pages[index]
+
+	page.Components = []tempest.MessageComponent{
+		tempest.ActionRowComponent{
+			Type: tempest.ACTION_ROW_COMPONENT_TYPE,
+			Components: []tempest.ActionRowChildComponent{
+				tempest.ButtonComponent{
+					Type:     tempest.BUTTON_COMPONENT_TYPE,
+					Style:    tempest.SECONDARY_BUTTON_STYLE,
+					Label:    "Previous",
+					CustomID: fmt.Sprintf("%s%s:%d", pagerPrefix, key, index-1),
+					Disabled: index == 0,
+				},
+				tempest.ButtonComponent{
+					Type:     tempest.BUTTON_COMPONENT_TYPE,
+					Style:    tempest.SECONDARY_BUTTON_STYLE,
+					Label:    fmt.Sprintf("%d / %d", index+1, len(pages)),
+					CustomID: pagerPrefix + key,
+					Disabled: true,
+				},
+				tempest.ButtonComponent{
+					Type:     tempest.BUTTON_COMPONENT_TYPE,
+					Style:    tempest.SECONDARY_BUTTON_STYLE,
+					Label:    "Next",
+					CustomID: fmt.Sprintf("%s%s:%d", pagerPrefix, key, index+1),
+					Disabled: index == len(pages)-1,
+				},
+			},
+		},
+	}
+
+	return page
+}
+
+// HandleComponent handles the pager buttons. The client acknowledges the
+// interaction before calling it, so it edits the original message.
+func HandleComponent(itx *tempest.ComponentInteraction) {
+	if !strings.HasPrefix(itx.Data.CustomID, pagerPrefix) {
+		slog.Warn("unknown component", "custom_id", itx.Data.CustomID)
+		return
+	}
+
+	key, index, ok := strings.Cut(strings.TrimPrefix(itx.Data.CustomID, pagerPrefix), ":")
+	page, err := strconv.Atoi(index)
+
+	if !ok || err != nil {
+		slog.Warn("malformed pager custom ID", "custom_id", itx.Data.CustomID)
+		return
+	}
+
+	var response tempest.ResponseMessageData
+
+	if pages, ok := loadPages(key); !ok {
+		// drop the buttons, there is nothing left to switch to
+		response = tempest.ResponseMessageData{
+			Content:    "-# These results have expired, run the command again to browse them.",
+			Components: []tempest.MessageComponent{},
+		}
+	} else {
+		response = pageWithButtons(key, pages, min(max(page, 0), len(pages)-1))
+	}
+
+	if err := updateMessage(itx, response); err != nil {
+		slog.Error("failed to switch page", "custom_id", itx.Data.CustomID, "error", err)
+	}
+}
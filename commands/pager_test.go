@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+// pressButton runs HandleComponent for the given custom ID and returns the
+// updates it made to the message
+func pressButton(t *testing.T, customID string) []tempest.ResponseMessageData {
+	t.Helper()
+
+	oldUpdateMessage := updateMessage
+	t.Cleanup(func() {
+		updateMessage = oldUpdateMessage
+	})
+
+	updates := []tempest.ResponseMessageData{}
+	updateMessage = func(itx *tempest.ComponentInteraction, response tempest.ResponseMessageData) error {
+		updates = append(updates, response)
+		return nil
+	}
+
+	HandleComponent(&tempest.ComponentInteraction{
+		Data: tempest.ComponentInteractionData{CustomID: customID},
+	})
+
+	return updates
+}
+
+func buttons(t *testing.T, response tempest.ResponseMessageData) []tempest.ButtonComponent {
+	t.Helper()
+
+	if len(response.Components) != 1 {
+		t.Fatalf("response has %d components, expected an action row", len(response.Components))
+	}
+
+	row := response.Components[0].(tempest.ActionRowComponent)
+	result := []tempest.ButtonComponent{}
+
+	for _, component := range row.Components {
+		result = append(result, component.(tempest.ButtonComponent))
+	}
+
+	return result
+}
+
+func testPages(count int) []tempest.ResponseMessageData {
+	pages := []tempest.ResponseMessageData{}
+
+	for i := 0; i < count; i++ {
+		pages = append(pages, tempest.ResponseMessageData{
+			Embeds: []tempest.Embed{{Title: fmt.Sprintf("Page %d", i+1)}},
+		})
+	}
+
+	return pages
+}
+
+func TestPageWithButtons(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		label    string
+		previous bool
+		next     bool
+	}{
+		{name: "first", index: 0, label: "1 / 3", previous: false, next: true},
+		{name: "middle", index: 1, label: "2 / 3", previous: true, next: true},
+		{name: "last", index: 2, label: "3 / 3", previous: true, next: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := pageWithButtons("key", testPages(3), tt.index)
+			row := buttons(t, page)
+
+			if page.Embeds[0].Title != fmt.Sprintf("Page %d", tt.index+1) {
+				t.Errorf("page title = %q", page.Embeds[0].Title)
+			}
+
+			if row[1].Label != tt.label {
+				t.Errorf("label = %q, expected %q", row[1].Label, tt.label)
+			}
+
+			if row[0].Disabled == tt.previous || row[2].Disabled == tt.next {
+				t.Errorf("previous enabled = %v, next enabled = %v, expected %v and %v", !row[0].Disabled, !row[2].Disabled, tt.previous, tt.next)
+			}
+		})
+	}
+}
+
+func TestHandleComponent(t *testing.T) {
+	key := storePages(testPages(3))
+
+	updates := pressButton(t, fmt.Sprintf("page:%s:2", key))
+
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, expected 1", len(updates))
+	}
+
+	if updates[0].Embeds[0].Title != "Page 3" {
+		t.Errorf("page title = %q, expected %q", updates[0].Embeds[0].Title, "Page 3")
+	}
+
+	next := buttons(t, updates[0])[2]
+
+	if !next.Disabled {
+		t.Errorf("next button is enabled on the last page")
+	}
+}
+
+func TestHandleComponent_Expired(t *testing.T) {
+	updates := pressButton(t, "page:unknown:1")
+
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, expected 1", len(updates))
+	}
+
+	if !strings.Contains(updates[0].Content, "expired") || len(updates[0].Components) != 0 {
+		t.Errorf("update = %+v, expected an expiry note without buttons", updates[0])
+	}
+}
+
+func TestHandleComponent_Malformed(t *testing.T) {
+	for _, customID := range []string{"other", "page:key", "page:key:next"} {
+		if updates := pressButton(t, customID); len(updates) != 0 {
+			t.Errorf("custom ID %q caused %d updates, expected none", customID, len(updates))
+		}
+	}
+}
+
+func TestReplyPages(t *testing.T) {
+	embeds := []tempest.Embed{}
+
+	for i := 0; i < 20; i++ {
+		embeds = append(embeds, tempest.Embed{Description: strings.Repeat("x", 4000)})
+	}
+
+	replies := runCommand(t, func(itx *tempest.CommandInteraction) {
+		reply(itx, tempest.ResponseMessageData{Embeds: embeds})
+	}, "test")
+
+	if len(replies) != 1 {
+		t.Fatalf("got %d replies, expected 1", len(replies))
+	}
+
+	checkLimits(t, replies[0].response.Embeds)
+
+	if label := buttons(t, replies[0].response)[1].Label; label != "1 / 20" {
+		t.Errorf("label = %q, expected %q", label, "1 / 20")
+	}
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+// Discord limits on messages and embeds, in characters
+const (
+	maxContentLength     = 2000
+	maxTitleLength       = 256
+	maxDescriptionLength = 4096
+	maxFieldNameLength   = 256
+	maxFieldValueLength  = 1024
+	maxFooterLength      = 2048
+	maxFields            = 25
+	maxEmbeds            = 10
+	maxTotalLength       = 6000
+)
+
+// maxPages limits how many pages a single reply may have
+const maxPages = 25
+
+const continued = " (continued)"
+
+// truncate shortens text to at most limit characters, marking the cut with an
+// ellipsis.
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+
+	return string(runes[:limit-1]) + "…"
+}
+
+// splitText splits text into chunks of at most limit characters, preferably
+// at line breaks.
+func splitText(text string, limit int) []string {
+	chunks := []string{}
+	chunk := ""
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for utf8.RuneCountInString(line) > limit {
+			if chunk != "" {
+				chunks = append(chunks, chunk)
+				chunk = ""
+			}
+
+			runes := []rune(line)
+			chunks = append(chunks, string(runes[:limit]))
+			line = string(runes[limit:])
+		}
+
+		if utf8.RuneCountInString(chunk)+utf8.RuneCountInString(line) > limit {
+			chunks = append(chunks, chunk)
+			chunk = ""
+		}
+
+		chunk += line
+	}
+
+	if strings.TrimSpace(chunk) != "" || len(chunks) == 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	for i := range chunks {
+		chunks[i] = strings.TrimRight(chunks[i], "\n")
+	}
+
+	return chunks
+}
+
+func embedLength(embed tempest.Embed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+
+	return length
+}
+
+// splitFields makes sure no field value exceeds the limit by splitting long
+// values into several fields.
+func splitFields(fields []tempest.EmbedField) []tempest.EmbedField {
+	result := []tempest.EmbedField{}
+
+	for _, field := range fields {
+		name := truncate(field.Name, maxFieldNameLength)
+		chunks := splitText(field.Value, maxFieldValueLength)
+
+		for i, chunk := range chunks {
+			if i > 0 {
+				name = truncate(field.Name, maxFieldNameLength-len(continued)) + continued
+			}
+
+			result = append(result, tempest.EmbedField{
+				Name:   name,
+				Value:  chunk,
+				Inline: field.Inline && len(chunks) == 1,
+			})
+		}
+	}
+
+	return result
+}
+
+// splitEmbed splits an embed into as many embeds as needed for each one of
+// them to respect Discord's limits. The continuations repeat the title.
+func splitEmbed(embed tempest.Embed) []tempest.Embed {
+	title := truncate(embed.Title, maxTitleLength)
+	continuedTitle := ""
+
+	if embed.Title != "" {
+		continuedTitle = truncate(embed.Title, maxTitleLength-len(continued)) + continued
+	}
+
+	embed.Title = title
+
+	if embed.Footer != nil {
+		embed.Footer = &tempest.EmbedFooter{
+			Text:    truncate(embed.Footer.Text, maxFooterLength),
+			IconURL: embed.Footer.IconURL,
+		}
+	}
+
+	descriptions := splitText(embed.Description, maxDescriptionLength)
+	fields := splitFields(embed.Fields)
+
+	embed.Description = descriptions[0]
+	embed.Fields = nil
+
+	embeds := []tempest.Embed{embed}
+
+	for _, description := range descriptions[1:] {
+		embeds = append(embeds, tempest.Embed{
+			Title:       continuedTitle,
+			Description: description,
+			Color:       embed.Color,
+		})
+	}
+
+	for _, field := range fields {
+		last := &embeds[len(embeds)-1]
+
+		if len(last.Fields) == maxFields || embedLength(*last)+embedLength(tempest.Embed{Fields: []tempest.EmbedField{field}}) > maxTotalLength {
+			embeds = append(embeds, tempest.Embed{
+				Title: continuedTitle,
+				Color: embed.Color,
+			})
+			last = &embeds[len(embeds)-1]
+		}
+
+		last.Fields = append(last.Fields, field)
+	}
+
+	return embeds
+}
+
+// paginate splits the embeds so that they respect Discord's limits and
+// groups them into pages that each fit into a single message.
+func paginate(embeds []tempest.Embed) [][]tempest.Embed {
+	pages := [][]tempest.Embed{}
+	page := []tempest.Embed{}
+	length := 0
+
+	for _, embed := range embeds {
+		for _, part := range splitEmbed(embed) {
+			partLength := embedLength(part)
+
+			if len(page) > 0 && (len(page) == maxEmbeds || length+partLength > maxTotalLength) {
+				pages = append(pages, page)
+				page = []tempest.Embed{}
+				length = 0
+			}
+
+			page = append(page, part)
+			length += partLength
+		}
+	}
+
+	if len(page) > 0 {
+		pages = append(pages, page)
+	}
+
+	return pages
+}
+
+// render turns groups of embeds into messages that respect Discord's limits.
+// Each group starts on a new page; groups that do not fit into a single
+// message are continued on the following pages.
+func render(content string, groups ...[]tempest.Embed) []tempest.ResponseMessageData {
+	pages := []tempest.ResponseMessageData{}
+
+	for _, group := range groups {
+		for _, embeds := range paginate(group) {
+			pages = append(pages, tempest.ResponseMessageData{Embeds: embeds})
+		}
+	}
+
+	if len(pages) == 0 {
+		pages = append(pages, tempest.ResponseMessageData{})
+	}
+
+	if len(pages) > maxPages {
+		content = strings.TrimSpace(fmt.Sprintf("%s\n-# Only the first %d of %d pages are shown.", content, maxPages, len(pages)))
+		pages = pages[:maxPages]
+	}
+
+	for i := range pages {
+		pages[i].Content = truncate(content, maxContentLength)
+	}
+
+	return pages
+}
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tempest "github.com/amatsagu/tempest"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		expected string
+	}{
+		{name: "short", text: "abc", limit: 5, expected: "abc"},
+		{name: "exact", text: "abcde", limit: 5, expected: "abcde"},
+		{name: "long", text: "abcdef", limit: 5, expected: "abcd…"},
+		{name: "multibyte", text: "ěščřžý", limit: 4, expected: "ěšč…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := truncate(tt.text, tt.limit); result != tt.expected {
+				t.Errorf("truncate(%q, %d) = %q, expected %q", tt.text, tt.limit, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		expected []string
+	}{
+		{name: "empty", text: "", limit: 10, expected: []string{""}},
+		{name: "fits", text: "one\ntwo", limit: 10, expected: []string{"one\ntwo"}},
+		{name: "split at line breaks", text: "one\ntwo\nthree", limit: 8, expected: []string{"one\ntwo", "three"}},
+		{name: "long line", text: "abcdefghij\nk", limit: 4, expected: []string{"abcd", "efgh", "ij\nk"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := splitText(tt.text, tt.limit); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("splitText(%q, %d) = %q, expected %q", tt.text, tt.limit, result, tt.expected)
+			}
+		})
+	}
+}
+
+// checkLimits fails the test if any of the embeds breaks Discord's limits
+func checkLimits(t *testing.T, embeds []tempest.Embed) {
+	t.Helper()
+
+	total := 0
+
+	for _, embed := range embeds {
+		if utf8.RuneCountInString(embed.Title) > maxTitleLength {
+			t.Errorf("title is %d characters long", utf8.RuneCountInString(embed.Title))
+		}
+
+		if utf8.RuneCountInString(embed.Description) > maxDescriptionLength {
+			t.Errorf("description is %d characters long", utf8.RuneCountInString(embed.Description))
+		}
+
+		if len(embed.Fields) > maxFields {
+			t.Errorf("embed has %d fields", len(embed.Fields))
+		}
+
+		for _, field := range embed.Fields {
+			if utf8.RuneCountInString(field.Value) > maxFieldValueLength {
+				t.Errorf("field %q is %d characters long", field.Name, utf8.RuneCountInString(field.Value))
+			}
+		}
+
+		total += embedLength(embed)
+	}
+
+	if len(embeds) > maxEmbeds {
+		t.Errorf("message has %d embeds", len(embeds))
+	}
+
+	if total > maxTotalLength {
+		t.Errorf("message embeds are %d characters long", total)
+	}
+}
+
+func TestSplitEmbed(t *testing.T) {
+	embed := tempest.Embed{
+		Title:       strings.Repeat("T", 300),
+		Description: strings.Repeat("line of the description\n", 400),
+		Fields: []tempest.EmbedField{
+			{Name: "Parameters", Value: strings.Repeat("parameter\n", 300)},
+		},
+	}
+
+	for i := 0; i < 30; i++ {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{Name: "Field", Value: "value", Inline: true})
+	}
+
+	embeds := splitEmbed(embed)
+
+	for _, part := range embeds {
+		checkLimits(t, []tempest.Embed{part})
+	}
+
+	if !strings.HasSuffix(embeds[1].Title, continued) {
+		t.Errorf("continuation title = %q, expected it to end with %q", embeds[1].Title, continued)
+	}
+
+	description := ""
+	fields := 0
+
+	for _, part := range embeds {
+		if part.Description != "" {
+			description += part.Description + "\n"
+		}
+
+		fields += len(part.Fields)
+	}
+
+	if description != embed.Description {
+		t.Errorf("description was not preserved when splitting")
+	}
+
+	// the parameters take three fields
+	if fields != 33 {
+		t.Errorf("split embeds have %d fields, expected 33", fields)
+	}
+}
+
+func TestRender(t *testing.T) {
+	embeds := []tempest.Embed{}
+
+	for i := 0; i < 15; i++ {
+		embeds = append(embeds, tempest.Embed{Title: "Embed", Description: strings.Repeat("x", 1000)})
+	}
+
+	pages := render("content", embeds, []tempest.Embed{{Title: "Second group"}})
+
+	if len(pages) != 4 {
+		t.Fatalf("render produced %d pages, expected 4", len(pages))
+	}
+
+	for _, page := range pages {
+		checkLimits(t, page.Embeds)
+
+		if page.Content != "content" {
+			t.Errorf("page content = %q, expected %q", page.Content, "content")
+		}
+	}
+
+	if pages[3].Embeds[0].Title != "Second group" {
+		t.Errorf("second group does not start on a new page")
+	}
+}
+
+func TestRender_TooManyPages(t *testing.T) {
+	groups := [][]tempest.Embed{}
+
+	for i := 0; i < maxPages+5; i++ {
+		groups = append(groups, []tempest.Embed{{Title: "Page"}})
+	}
+
+	pages := render("", groups...)
+
+	if len(pages) != maxPages {
+		t.Fatalf("render produced %d pages, expected %d", len(pages), maxPages)
+	}
+
+	if !strings.Contains(pages[0].Content, "Only the first 25 of 30 pages are shown.") {
+		t.Errorf("page content = %q, expected a note about the missing pages", pages[0].Content)
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	pages := render(strings.Repeat("x", 3000))
+
+	if len(pages) != 1 {
+		t.Fatalf("render produced %d pages, expected 1", len(pages))
+	}
+
+	if length := utf8.RuneCountInString(pages[0].Content); length != maxContentLength {
+		t.Errorf("content is %d characters long, expected %d", length, maxContentLength)
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	embeds := []tempest.Embed{}
+
+	for i := 0; i < 50; i++ {
+		embeds = append(embeds, tempest.Embed{Title: "Embed", Description: strings.Repeat("line\n", 500)})
+	}
+
+	for i := 0; i < b.N; i++ {
+		render("", embeds)
+	}
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+const codeFormatsHelp = "Codes can be given in hexadecimal (`0xC0000005`, `C0000005`, `C0000005h`), " +
+	"decimal (`3221225477`, `-1073741819`, `0n5`), by their symbolic name (`STATUS_ACCESS_VIOLATION`, `E_FAIL`) " +
+	"or as a macro (`HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)`). " +
+	"Values copied from WinDbg (``0xffffffff`c0000005``) or C headers (`0x80070005L`) work too."
+
+// sendReply responds to the interaction. Tests replace it to capture the
+// replies without talking to Discord.
+var sendReply = func(itx *tempest.CommandInteraction, response tempest.ResponseMessageData, ephemeral bool) error {
+	return itx.SendReply(response, ephemeral, nil)
+}
+
+// deferReply tells Discord the reply will take a while, for commands that
+// download attachments. Tests replace it like sendReply.
+var deferReply = func(itx *tempest.CommandInteraction) error {
+	return itx.Defer(false)
+}
+
+// sendDeferredError replaces the public placeholder left by deferReply with
+// an error only the user sees. Replying to a deferred interaction edits the
+// placeholder, which keeps it public, so the error goes in a follow-up
+// instead. Tests replace it like sendReply.
+var sendDeferredError = func(itx *tempest.CommandInteraction, response tempest.ResponseMessageData) error {
+	if err := itx.DeleteReply(); err != nil {
+		return err
+	}
+
+	_, err := itx.SendFollowUp(response, true)
+	return err
+}
+
+func reply(itx *tempest.CommandInteraction, response tempest.ResponseMessageData) {
+	replyPages(itx, 0, response.Content, response.Embeds)
+}
+
+// replyPages responds with the embeds split to respect Discord's limits. Each
+// group of embeds starts on a new page. If there is more than one page, the
+// reply starts at the given page and gets buttons to switch between them.
+func replyPages(itx *tempest.CommandInteraction, start int, content string, groups ...[]tempest.Embed) {
+	pages := render(content, groups...)
+	response := pages[0]
+
+	if len(pages) > 1 {
+		response = pageWithButtons(storePages(pages), pages, min(max(start, 0), len(pages)-1))
+	}
+
+	if err := sendReply(itx, response, false); err != nil {
+		slog.Error("failed to send reply", "command", itx.Data.Name, "error", err)
+	}
+}
+
+// replyError lets the user know their request could not be fulfilled. The
+// message is only visible to the user who invoked the command.
+func replyError(itx *tempest.CommandInteraction, format string, args ...any) {
+	response := tempest.ResponseMessageData{
+		Content: fmt.Sprintf(format, args...),
+	}
+
+	if err := sendReply(itx, response, true); err != nil {
+		slog.Error("failed to send error reply", "command", itx.Data.Name, "error", err)
+	}
+}
+
+// replyDeferredError is replyError for commands that called deferReply
+func replyDeferredError(itx *tempest.CommandInteraction, format string, args ...any) {
+	response := tempest.ResponseMessageData{
+		Content: fmt.Sprintf(format, args...),
+	}
+
+	if err := sendDeferredError(itx, response); err != nil {
+		slog.Error("failed to send error reply", "command", itx.Data.Name, "error", err)
+	}
+}
+
+// replyInvalidCode explains why value was not understood as an error code
+// and shows the formats that are.
+func replyInvalidCode(itx *tempest.CommandInteraction, value string, err error) {
+	slog.Info("could not understand code", "command", itx.Data.Name, "value", value, "error", err)
+
+	if value == "" {
+		replyError(itx, "No code was given.\n%s", codeFormatsHelp)
+		return
+	}
+
+	replyError(itx, "Could not understand `%s`: %s.\n%s", value, describeParseError(err), codeFormatsHelp)
+}
+
+func describeParseError(err error) string {
+	switch {
+	case err == nil:
+		return "it is not a known symbolic name"
+	case errors.Is(err, strconv.ErrRange):
+		return "the number does not fit into a 32-bit code"
+	case errors.Is(err, strconv.ErrSyntax):
+		return "it is neither a number nor a known symbolic name"
+	}
+
+	return err.Error()
+}
+
+// parseCode reads a code option and returns its possible values along with
+// a note saying how it was read. Plain hex codes need no explanation, so
+// the note is empty for them.
+func parseCode(value string) ([]uint32, string, error) {
+	if util.IsMacro(value) {
+		code, err := util.EvaluateMacro(value, resolveName)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		return []uint32{code}, fmt.Sprintf("-# Evaluated %s to `0x%08X`.", inlineCode(strings.TrimSpace(value)), code), nil
+	}
+
+	interpretations, err := util.InterpretCode(value)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	codes := make([]uint32, len(interpretations))
+	readings := make([]string, len(interpretations))
+
+	for i, interpretation := range interpretations {
+		codes[i] = interpretation.Code
+		readings[i] = fmt.Sprintf("%s (`0x%08X`)", interpretation.Format, interpretation.Code)
+	}
+
+	if len(interpretations) == 1 && interpretations[0].Format == "hexadecimal" {
+		return codes, "", nil
+	}
+
+	return codes, fmt.Sprintf("-# Read %s as %s.", inlineCode(strings.TrimSpace(value)), strings.Join(readings, " or ")), nil
+}
+
+// resolveName returns the code of a symbolic name used as a macro argument,
+// which may also be a facility such as FACILITY_WIN32.
+func resolveName(name string) (uint32, bool) {
+	found := findExactName(name)
+
+	for _, kind := range repo.Kinds {
+		if len(found[kind]) > 0 {
+			return found[kind][0].Code, true
+		}
+	}
+
+	if facility, ok := repoInstance.FindFacility(name); ok {
+		return uint32(facility), true
+	}
+
+	return 0, false
+}
+
+// inlineCode formats text as inline code, which takes double backticks if
+// the text contains one, as WinDbg values do.
+func inlineCode(text string) string {
+	if strings.Contains(text, "`") {
+		return "`` " + text + " ``"
+	}
+
+	return "`" + text + "`"
+}
+
+// stringOption returns the value of the named string option.
+func stringOption(itx *tempest.CommandInteraction, name string) (string, bool) {
+	value, ok := itx.GetOptionValue(name)
+
+	if !ok {
+		return "", false
+	}
+
+	text, ok := value.(string)
+
+	return text, ok
+}
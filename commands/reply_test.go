@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+type capturedReply struct {
+	response  tempest.ResponseMessageData
+	ephemeral bool
+}
+
+// createTestRepo creates a small indexed repository for handler tests
+func createTestRepo() repo.Repo {
+	testRepo := repo.New(
+		repo.NewBugCheckCatalog("bug check", repo.BugCheckRepo{
+			{Code: 0x7E, Name: "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED", Description: "A system thread generated an exception that the error handler did not catch.", Parameters: []string{"The exception code that was not handled"}},
+		}),
+		repo.NewListCatalog("HRESULT", repo.KindHResult, []repo.ErrorInfo{
+			{Code: 0x80004005, Name: "E_FAIL", Description: "Unspecified error"},
+			{Code: 0x80000018, Name: "E_ILLEGAL_DELEGATE_ASSIGNMENT", Description: "A delegate was assigned when not allowed.", Introduced: "10.0.19041.0"},
+		}, map[uint16]string{7: "FACILITY_WIN32", 36: "FACILITY_WINDOWSUPDATE"}),
+		repo.NewListCatalog("Windows Update Agent", repo.KindHResult, []repo.ErrorInfo{
+			{Code: 0x8024402C, Name: "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", Description: "Same as ERROR_WINHTTP_NAME_NOT_RESOLVED."},
+		}, nil),
+		repo.NewListCatalog("Win32 error", repo.KindWin32Error, []repo.ErrorInfo{
+			{Code: 2, Name: "ERROR_FILE_NOT_FOUND", Description: "The system cannot find the file specified."},
+			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
+		}, nil),
+		repo.NewListCatalog("NTSTATUS", repo.KindNTStatus, []repo.ErrorInfo{
+			{Code: 0xC0000005, Name: "STATUS_ACCESS_VIOLATION", Description: "The instruction referenced memory it could not access."},
+			{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "A process has requested access to an object but has not been granted those access rights."},
+		}, map[uint16]string{7: "FACILITY_NTWIN32"}),
+	)
+	testRepo.NTStatusMapping = repo.NTStatusMappingRepo{
+		{NTStatus: 0xC0000022, Win32Error: 5},
+	}
+	testRepo.Manifest = repo.Manifest{
+		{Name: "HRESULT", Type: "hresult", File: "hresult.yml", Source: "winerror.h", SDK: "10.0.26100.0", Generated: "2026-10-17", SHA256: "33bec4ac7c22d6adeb2a0afcccb947be459cbaafac4342aae2a1b55de4318409", Entries: 1},
+		{Name: "Win32 error", Type: "win32error", File: "win32error.yml", Entries: 2},
+	}
+	testRepo.BuildIndex()
+
+	return testRepo
+}
+
+// runCommand runs handler with the given string options and returns the
+// replies it sent
+func runCommand(t *testing.T, handler func(*tempest.CommandInteraction), name string, options ...string) []capturedReply {
+	t.Helper()
+
+	oldRepo, oldSendReply, oldDeferReply, oldSendDeferredError := repoInstance, sendReply, deferReply, sendDeferredError
+	t.Cleanup(func() {
+		repoInstance, sendReply, deferReply, sendDeferredError = oldRepo, oldSendReply, oldDeferReply, oldSendDeferredError
+	})
+
+	deferred := false
+	deferReply = func(itx *tempest.CommandInteraction) error {
+		deferred = true
+		return nil
+	}
+
+	repoInstance = createTestRepo()
+
+	// like Discord, a reply to a deferred interaction edits the public
+	// placeholder and ignores the ephemeral flag
+	replies := []capturedReply{}
+	sendReply = func(itx *tempest.CommandInteraction, response tempest.ResponseMessageData, ephemeral bool) error {
+		replies = append(replies, capturedReply{response, ephemeral && !deferred})
+		return nil
+	}
+
+	sendDeferredError = func(itx *tempest.CommandInteraction, response tempest.ResponseMessageData) error {
+		if !deferred {
+			t.Error("sent a deferred error without deferring the reply")
+		}
+
+		replies = append(replies, capturedReply{response, true})
+		return nil
+	}
+
+	itx := &tempest.CommandInteraction{
+		Data: tempest.CommandInteractionData{Name: name},
+	}
+
+	for i := 0; i+1 < len(options); i += 2 {
+		itx.Data.Options = append(itx.Data.Options, tempest.CommandInteractionOption{
+			Name:  options[i],
+			Type:  tempest.STRING_OPTION_TYPE,
+			Value: options[i+1],
+		})
+	}
+
+	handler(itx)
+
+	return replies
+}
+
+// runAttachmentCommand runs handler with a file option holding an
+// attachment that downloads as data, or fails to download if data is nil
+func runAttachmentCommand(t *testing.T, handler func(*tempest.CommandInteraction), name string, fileName string, data []byte) []capturedReply {
+	t.Helper()
+
+	url := "https://cdn.discordapp.com/attachments/1/2/" + fileName
+
+	oldDownloadAttachment := downloadAttachment
+	t.Cleanup(func() {
+		downloadAttachment = oldDownloadAttachment
+	})
+
+	downloadAttachment = func(requested string, limit int64) ([]byte, error) {
+		if requested != url {
+			t.Errorf("downloaded %q, expected %q", requested, url)
+		}
+
+		if data == nil {
+			return nil, errors.New("connection reset")
+		}
+
+		return data, nil
+	}
+
+	withAttachment := func(itx *tempest.CommandInteraction) {
+		itx.Data.Resolved = &tempest.InteractionDataResolved{
+			Attachments: map[tempest.Snowflake]tempest.Attachment{
+				2: {ID: 2, FileName: fileName, URL: url},
+			},
+		}
+
+		handler(itx)
+	}
+
+	return runCommand(t, withAttachment, name, "file", "2")
+}
+
+// expectErrorReply checks that exactly one ephemeral reply containing all of
+// the given fragments was sent
+func expectErrorReply(t *testing.T, replies []capturedReply, fragments ...string) {
+	t.Helper()
+
+	if len(replies) != 1 {
+		t.Fatalf("handler sent %d replies, expected 1", len(replies))
+	}
+
+	if !replies[0].ephemeral {
+		t.Errorf("error reply is not ephemeral")
+	}
+
+	if len(replies[0].response.Embeds) != 0 {
+		t.Errorf("error reply has %d embeds, expected none", len(replies[0].response.Embeds))
+	}
+
+	for _, fragment := range fragments {
+		if !strings.Contains(replies[0].response.Content, fragment) {
+			t.Errorf("error reply %q does not contain %q", replies[0].response.Content, fragment)
+		}
+	}
+}
+
+// expectEmbedReply checks that exactly one public reply with embeds was sent
+func expectEmbedReply(t *testing.T, replies []capturedReply) tempest.ResponseMessageData {
+	t.Helper()
+
+	if len(replies) != 1 {
+		t.Fatalf("handler sent %d replies, expected 1", len(replies))
+	}
+
+	if replies[0].ephemeral {
+		t.Errorf("reply is ephemeral")
+	}
+
+	if len(replies[0].response.Embeds) == 0 {
+		t.Errorf("reply has no embeds, content %q", replies[0].response.Content)
+	}
+
+	return replies[0].response
+}
+
+func TestDescribeParseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no error", err: nil, expected: "it is not a known symbolic name"},
+		{name: "range error", err: &strconv.NumError{Func: "ParseUint", Num: "0x100000000", Err: strconv.ErrRange}, expected: "the number does not fit into a 32-bit code"},
+		{name: "syntax error", err: &strconv.NumError{Func: "ParseUint", Num: "xyz", Err: strconv.ErrSyntax}, expected: "it is neither a number nor a known symbolic name"},
+		{name: "other error", err: errors.New("empty string"), expected: "empty string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := describeParseError(tt.err); result != tt.expected {
+				t.Errorf("describeParseError(%v) = %q, expected %q", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
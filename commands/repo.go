@@ -4,9 +4,9 @@ import "github.com/dhrdlicka/errorbot/repo"
 
 var repoInstance repo.Repo
 
-func LoadRepo() error {
+func LoadRepo(dir string) error {
 	var err error
-	repoInstance, err = repo.Load()
+	repoInstance, err = repo.Load(dir)
 
 	return err
 }
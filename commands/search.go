@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+const (
+	searchPageSize = 10
+	// maxSearchDescriptionLength is how much of each description search
+	// results show
+	maxSearchDescriptionLength = 200
+)
+
+var SearchCommand = tempest.Command{
+	Type:        tempest.CHAT_INPUT_COMMAND_TYPE,
+	Name:        "search",
+	Description: "Search Windows error codes by their description",
+	Options: []tempest.CommandOption{
+		{
+			Type:        tempest.STRING_OPTION_TYPE,
+			Name:        "query",
+			Description: "Words from the error message",
+			Required:    true,
+		},
+		{
+			Type:        tempest.INTEGER_OPTION_TYPE,
+			Name:        "page",
+			Description: "Page of the results",
+			MinValue:    1,
+		},
+	},
+	SlashCommandHandler: handleSearch,
+}
+
+func handleSearch(itx *tempest.CommandInteraction) {
+	query, _ := stringOption(itx, "query")
+
+	if strings.TrimSpace(query) == "" {
+		replyError(itx, "Enter some words from the error message to search for.")
+		return
+	}
+
+	page := 1
+
+	if value, ok := itx.GetOptionValue("page"); ok {
+		if number, ok := value.(float64); ok {
+			page = int(number)
+		}
+	}
+
+	results := repoInstance.Search(query)
+
+	if len(results) == 0 {
+		replyError(itx, "Could not find any error codes matching %s", query)
+		return
+	}
+
+	pages := min((len(results)+searchPageSize-1)/searchPageSize, maxPages)
+	groups := make([][]tempest.Embed, pages)
+
+	for i := range groups {
+		start := i * searchPageSize
+		end := min(start+searchPageSize, len(results))
+
+		groups[i] = []tempest.Embed{
+			{
+				Title:       fmt.Sprintf("Search results for \"%s\"", query),
+				Description: formatSearchResults(results[start:end]),
+				Footer: &tempest.EmbedFooter{
+					Text: fmt.Sprintf("Page %d of %d (%d results)", i+1, pages, len(results)),
+				},
+			},
+		}
+	}
+
+	content := ""
+
+	if pages*searchPageSize < len(results) {
+		content = fmt.Sprintf("-# Only the first %d results are shown, try a more specific query.", pages*searchPageSize)
+	}
+
+	replyPages(itx, page-1, content, groups...)
+}
+
+func formatSearchResults(results []repo.SearchResult) string {
+	var result []byte
+
+	for _, item := range results {
+		result = fmt.Appendf(result, "`%s` (`0x%08X`, %s)\n", item.Name, item.Code, item.Kind)
+
+		description, _, _ := strings.Cut(strings.TrimSpace(item.Description), "\n")
+
+		result = fmt.Appendf(result, "> %s\n", truncate(strings.TrimSpace(description), maxSearchDescriptionLength))
+	}
+
+	return string(result)
+}
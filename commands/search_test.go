@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+func TestHandleSearch_MalformedInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		fragments []string
+	}{
+		{name: "empty", query: "", fragments: []string{"Enter some words"}},
+		{name: "whitespace", query: "   ", fragments: []string{"Enter some words"}},
+		{name: "no match", query: "printer on fire", fragments: []string{"Could not find any error codes matching printer on fire"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := runCommand(t, handleSearch, "search", "query", tt.query)
+			expectErrorReply(t, replies, tt.fragments...)
+		})
+	}
+}
+
+func TestHandleSearch_Found(t *testing.T) {
+	response := expectEmbedReply(t, runCommand(t, handleSearch, "search", "query", "cannot find the file"))
+
+	if response.Embeds[0].Footer == nil || response.Embeds[0].Footer.Text != "Page 1 of 1 (1 results)" {
+		t.Errorf("embed footer = %v, expected page 1 of 1", response.Embeds[0].Footer)
+	}
+}
+
+func TestFormatSearchResults_Truncate(t *testing.T) {
+	description := strings.Repeat("é", 250)
+	result := formatSearchResults([]repo.SearchResult{{ErrorInfo: repo.ErrorInfo{Code: 5, Name: "ERROR_LONG", Description: description}, Kind: repo.KindWin32Error}})
+
+	if !utf8.ValidString(result) {
+		t.Fatalf("formatSearchResults() = %q, which is not valid UTF-8", result)
+	}
+
+	if expected := "> " + strings.Repeat("é", maxSearchDescriptionLength-1) + "…\n"; !strings.HasSuffix(result, expected) {
+		t.Errorf("formatSearchResults() = %q, expected the description cut to %d characters", result, maxSearchDescriptionLength)
+	}
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tempest "github.com/amatsagu/tempest"
+	"github.com/dhrdlicka/errorbot/updatelog"
+)
+
+// maxLogSize limits how much of a log is downloaded, CBS.log grows large
+// before it is rotated
+const maxLogSize = 32 << 20
+
+// maxLogLineLength limits how much of the first failing line is quoted
+const maxLogLineLength = 300
+
+// logTimeLayout formats log times, which are the local time of the machine
+// that wrote the log and so cannot be shown as Discord timestamps
+const logTimeLayout = time.DateTime
+
+var UpdateLogCommand = tempest.Command{
+	Type:        tempest.CHAT_INPUT_COMMAND_TYPE,
+	Name:        "updatelog",
+	Description: "Find the failures in a CBS, DISM or Windows Update log",
+	Options: []tempest.CommandOption{
+		{
+			Type:        tempest.ATTACHMENT_OPTION_TYPE,
+			Name:        "file",
+			Description: "CBS.log, DISM.log or WindowsUpdate.log",
+			Required:    true,
+		},
+	},
+	SlashCommandHandler: handleUpdateLog,
+}
+
+func handleUpdateLog(itx *tempest.CommandInteraction) {
+	attachment, ok := attachmentOption(itx, "file")
+
+	if !ok {
+		replyError(itx, "Attach a log to analyze.")
+		return
+	}
+
+	if err := deferReply(itx); err != nil {
+		slog.Error("failed to defer reply", "command", itx.Data.Name, "error", err)
+		return
+	}
+
+	data, err := downloadAttachment(attachment.URL, maxLogSize)
+
+	if err != nil {
+		slog.Error("failed to download attachment", "command", itx.Data.Name, "file", attachment.FileName, "error", err)
+		replyDeferredError(itx, "Could not download %s.", inlineCode(attachment.FileName))
+		return
+	}
+
+	analysis, err := updatelog.Analyze(repoInstance, bytes.NewReader(data))
+
+	if errors.Is(err, updatelog.ErrNoEntries) {
+		replyDeferredError(itx, "Could not read %s: it is not a CBS, DISM or Windows Update log. "+
+			"They are found in `C:\\Windows\\Logs\\CBS`, `C:\\Windows\\Logs\\DISM` and, after running `Get-WindowsUpdateLog`, on the desktop.", inlineCode(attachment.FileName))
+		return
+	} else if err != nil {
+		slog.Error("failed to analyze log", "command", itx.Data.Name, "file", attachment.FileName, "error", err)
+		replyDeferredError(itx, "Could not read %s.", inlineCode(attachment.FileName))
+		return
+	}
+
+	if len(analysis.Failures) == 0 {
+		replyDeferredError(itx, "Could not find any failure codes in %s.", inlineCode(attachment.FileName))
+		return
+	}
+
+	content := fmt.Sprintf("-# Read %s as %s.", inlineCode(attachment.FileName), analysis.Format)
+
+	replyPages(itx, 0, content, []tempest.Embed{createUpdateLogEmbed(analysis)})
+}
+
+func createUpdateLogEmbed(analysis updatelog.Analysis) tempest.Embed {
+	first := analysis.Failures[0].First
+
+	description := fmt.Sprintf("Covers %s to %s (%d lines).\n\n**First failure** at %s, line %d:\n```\n%s\n```",
+		analysis.Start.Format(logTimeLayout), analysis.End.Format(logTimeLayout), analysis.Lines,
+		first.Time.Format(logTimeLayout), first.Line, truncate(first.Text, maxLogLineLength))
+
+	embed := tempest.Embed{
+		Title:       fmt.Sprintf("Failures in %s", analysis.Format),
+		Description: description,
+	}
+
+	for _, failure := range analysis.Failures {
+		embed.Fields = append(embed.Fields, tempest.EmbedField{
+			Name:  fmt.Sprintf("0x%08X", failure.Code),
+			Value: formatLogFailure(failure),
+		})
+	}
+
+	return embed
+}
+
+func formatLogFailure(failure updatelog.Failure) string {
+	lines := []string{}
+
+	for _, match := range failure.Matches {
+		lines = append(lines, fmt.Sprintf("`%s` (%s)", match.Name, match.Kind))
+
+		if description := firstLine(match.Description); description != "" {
+			lines = append(lines, "> "+description)
+		}
+	}
+
+	if len(failure.Matches) == 0 {
+		if failure.Name != "" {
+			lines = append(lines, fmt.Sprintf("Not a known error code, the log calls it `%s`", failure.Name))
+		} else {
+			lines = append(lines, "Not a known error code")
+		}
+	}
+
+	seen := fmt.Sprintf("Seen once at %s (line %d)", failure.First.Time.Format(logTimeLayout), failure.First.Line)
+
+	if failure.Count > 1 {
+		seen = fmt.Sprintf("Seen %d times, first at %s (line %d), last at %s (line %d)", failure.Count,
+			failure.First.Time.Format(logTimeLayout), failure.First.Line,
+			failure.Last.Time.Format(logTimeLayout), failure.Last.Line)
+	}
+
+	lines = append(lines, "-# "+seen)
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDISMLog = `2024-03-05 21:15:00, Info                  DISM   DISM.EXE: <----- Starting Dism.exe session ----->
+2024-03-05 21:15:30, Error                 DISM   DISM Package Manager: PID=4321 TID=8765 Failed finalizing changes. - CDISMPackageManager::Internal_Finalize(hr:0x800f081f)
+2024-03-05 21:15:31, Error                 DISM   DISM Package Manager: PID=4321 TID=8765 The source files could not be found. - CPackageManagerCLIHandler::ExecuteCmdLine(hr:0x800f081f)
+2024-03-05 21:15:32, Info                  DISM   Access check failed [HRESULT = 0x80070005 - E_ACCESSDENIED]
+2024-03-05 21:15:40, Info                  DISM   DISM.EXE: <----- Ending Dism.exe session ----->
+`
+
+func TestHandleUpdateLog(t *testing.T) {
+	replies := runAttachmentCommand(t, handleUpdateLog, "updatelog", "dism.log", []byte(testDISMLog))
+	response := expectEmbedReply(t, replies)
+	embed := response.Embeds[0]
+
+	if response.Content != "-# Read `dism.log` as DISM.log." {
+		t.Errorf("content is %q", response.Content)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "time span", text: embed.Description, expected: "Covers 2024-03-05 21:15:00 to 2024-03-05 21:15:40 (5 lines)."},
+		{name: "first failure", text: embed.Description, expected: "**First failure** at 2024-03-05 21:15:30, line 2:\n```\n2024-03-05 21:15:30, Error"},
+		{name: "unknown code", text: embed.Fields[0].Value, expected: "Not a known error code\n-# Seen 2 times, first at 2024-03-05 21:15:30 (line 2), last at 2024-03-05 21:15:31 (line 3)"},
+		{name: "known code", text: embed.Fields[1].Value, expected: "`HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)` (HRESULT)\n> Access is denied.\n-# Seen once at 2024-03-05 21:15:32 (line 4)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !strings.Contains(test.text, test.expected) {
+				t.Errorf("%q does not contain %q", test.text, test.expected)
+			}
+		})
+	}
+
+	if len(embed.Fields) != 2 || embed.Fields[0].Name != "0x800F081F" || embed.Fields[1].Name != "0x80070005" {
+		t.Errorf("fields are %+v", embed.Fields)
+	}
+}
+
+func TestHandleUpdateLog_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "download failure", data: nil, expected: "Could not download `dism.log`"},
+		{name: "not a log", data: []byte("Error 0x800F081F"), expected: "it is not a CBS, DISM or Windows Update log"},
+		{name: "no failures", data: []byte("2024-03-05 21:15:00, Info                  DISM   DISM.EXE: Starting\n"), expected: "Could not find any failure codes in `dism.log`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectErrorReply(t, runAttachmentCommand(t, handleUpdateLog, "updatelog", "dism.log", test.data), test.expected)
+		})
+	}
+}
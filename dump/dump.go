@@ -0,0 +1,124 @@
+// Package dump reads Windows crash dumps: user-mode minidumps (MDMP) and
+// kernel dumps (PAGEDU64, PAGEDUMP), which include the small memory dumps
+// Windows saves to C:\Windows\Minidump when it bug checks.
+package dump
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnknownFormat is returned for files that are not crash dumps.
+var ErrUnknownFormat = errors.New("not a Windows crash dump")
+
+// ErrTruncated is returned for dumps that end before their headers do.
+var ErrTruncated = errors.New("the dump is truncated")
+
+// ErrCorrupt is returned for dumps whose headers make no sense.
+var ErrCorrupt = errors.New("the dump is corrupted")
+
+type Format string
+
+const (
+	FormatMinidump Format = "MDMP"
+	FormatKernel64 Format = "PAGEDU64"
+	FormatKernel32 Format = "PAGEDUMP"
+)
+
+// Dump holds what the headers of a crash dump say about the crash.
+type Dump struct {
+	Format Format
+	// HasBugCheck is set for kernel dumps, which Windows writes when it bug
+	// checks
+	HasBugCheck        bool
+	BugCheckCode       uint32
+	BugCheckParameters [4]uint64
+	// MajorVersion and MinorVersion are only known for minidumps, kernel
+	// dumps only have the build number
+	MajorVersion   uint32
+	MinorVersion   uint32
+	BuildNumber    uint32
+	ProcessorCount uint32
+	Architecture   string
+	// DumpType is the kind of kernel dump, see DumpTypeName
+	DumpType uint32
+	// Modules are the images loaded when the dump was written. Of the
+	// kernel dumps, only small memory dumps list them.
+	Modules []Module
+	// Exception is the exception a minidump was written for, if any
+	Exception *Exception
+}
+
+// Module is an image loaded in the process or the system that crashed.
+type Module struct {
+	// Name is the path of the image as the dump has it
+	Name          string
+	Base          uint64
+	Size          uint32
+	TimeDateStamp uint32
+}
+
+var byteOrder = binary.LittleEndian
+
+// Parse reads the headers of a crash dump.
+func Parse(r io.ReaderAt) (*Dump, error) {
+	signature, err := readAt(r, 0, 8)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case string(signature[:4]) == "MDMP":
+		return parseMinidump(r)
+	case string(signature) == "PAGEDU64":
+		return parseKernelDump(r, kernelDump64)
+	case string(signature) == "PAGEDUMP":
+		return parseKernelDump(r, kernelDump32)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// DumpTypeName describes the kind of a kernel dump.
+func (dump Dump) DumpTypeName() string {
+	switch dump.DumpType {
+	case 1:
+		return "Complete memory dump"
+	case 2:
+		return "Kernel memory dump"
+	case 3:
+		return "Header only dump"
+	case 4:
+		return "Small memory dump"
+	case 5:
+		return "Complete memory dump (bitmap)"
+	case 6:
+		return "Kernel memory dump (bitmap)"
+	case 7:
+		return "Automatic memory dump"
+	}
+
+	return fmt.Sprintf("Unknown dump type %d", dump.DumpType)
+}
+
+// readAt reads exactly size bytes at offset.
+func readAt(r io.ReaderAt, offset int64, size int) ([]byte, error) {
+	buffer := make([]byte, size)
+
+	if size == 0 {
+		return buffer, nil
+	}
+
+	if _, err := r.ReadAt(buffer, offset); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrTruncated
+		}
+
+		return nil, err
+	}
+
+	return buffer, nil
+}
@@ -0,0 +1,414 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// buildMinidump generates a minidump with a system info stream, a module
+// list stream and, if exception is not nil, an exception stream with the
+// context of the thread
+func buildMinidump(architecture uint16, processors uint8, build uint32, modules []Module, exception *Exception) []byte {
+	streamCount := 2
+
+	if exception != nil {
+		streamCount = 3
+	}
+
+	systemInfoRva := minidumpHeaderSize + streamCount*directoryEntrySize
+	moduleListRva := systemInfoRva + 56
+	stringsRva := moduleListRva + 4 + len(modules)*moduleEntrySize
+
+	data := make([]byte, stringsRva)
+
+	copy(data, "MDMP")
+	binary.LittleEndian.PutUint32(data[4:], 0xA793)
+	binary.LittleEndian.PutUint32(data[8:], uint32(streamCount))
+	binary.LittleEndian.PutUint32(data[12:], minidumpHeaderSize)
+
+	directory := data[minidumpHeaderSize:]
+	binary.LittleEndian.PutUint32(directory, systemInfoStream)
+	binary.LittleEndian.PutUint32(directory[4:], 56)
+	binary.LittleEndian.PutUint32(directory[8:], uint32(systemInfoRva))
+	binary.LittleEndian.PutUint32(directory[12:], moduleListStream)
+	binary.LittleEndian.PutUint32(directory[16:], uint32(4+len(modules)*moduleEntrySize))
+	binary.LittleEndian.PutUint32(directory[20:], uint32(moduleListRva))
+
+	systemInfo := data[systemInfoRva:]
+	binary.LittleEndian.PutUint16(systemInfo, architecture)
+	systemInfo[6] = processors
+	binary.LittleEndian.PutUint32(systemInfo[8:], 10)
+	binary.LittleEndian.PutUint32(systemInfo[12:], 0)
+	binary.LittleEndian.PutUint32(systemInfo[16:], build)
+
+	binary.LittleEndian.PutUint32(data[moduleListRva:], uint32(len(modules)))
+
+	for i, module := range modules {
+		entry := data[moduleListRva+4+i*moduleEntrySize:]
+		binary.LittleEndian.PutUint64(entry, module.Base)
+		binary.LittleEndian.PutUint32(entry[8:], module.Size)
+		binary.LittleEndian.PutUint32(entry[16:], module.TimeDateStamp)
+		binary.LittleEndian.PutUint32(entry[20:], uint32(len(data)))
+
+		name := utf16.Encode([]rune(module.Name))
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(name)*2))
+
+		for _, unit := range name {
+			data = binary.LittleEndian.AppendUint16(data, unit)
+		}
+	}
+
+	if exception != nil {
+		// the module names may have moved data, so directory is stale
+		entry := data[minidumpHeaderSize+2*directoryEntrySize:]
+		binary.LittleEndian.PutUint32(entry, exceptionStream)
+		binary.LittleEndian.PutUint32(entry[4:], exceptionStreamSize)
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(data)))
+
+		data = append(data, buildException(exception, processorArchitectures[architecture], uint32(len(data)))...)
+	}
+
+	return data
+}
+
+// buildException generates an exception stream at rva followed by the
+// context of the thread
+func buildException(exception *Exception, architecture string, rva uint32) []byte {
+	layout := contextLayouts[architecture]
+	data := make([]byte, exceptionStreamSize+layout.size)
+
+	binary.LittleEndian.PutUint32(data, exception.ThreadID)
+	binary.LittleEndian.PutUint32(data[8:], exception.Code)
+	binary.LittleEndian.PutUint64(data[24:], exception.Address)
+	binary.LittleEndian.PutUint32(data[32:], uint32(len(exception.Parameters)))
+
+	for i, parameter := range exception.Parameters {
+		binary.LittleEndian.PutUint64(data[40+i*8:], parameter)
+	}
+
+	binary.LittleEndian.PutUint32(data[160:], uint32(layout.size))
+	binary.LittleEndian.PutUint32(data[164:], rva+exceptionStreamSize)
+
+	context := data[exceptionStreamSize:]
+
+	if layout.pointerSize == 4 {
+		binary.LittleEndian.PutUint32(context[layout.instructionPointer:], uint32(exception.InstructionPointer))
+		binary.LittleEndian.PutUint32(context[layout.stackPointer:], uint32(exception.StackPointer))
+	} else {
+		binary.LittleEndian.PutUint64(context[layout.instructionPointer:], exception.InstructionPointer)
+		binary.LittleEndian.PutUint64(context[layout.stackPointer:], exception.StackPointer)
+	}
+
+	return data
+}
+
+// buildKernelDump generates the header of a 64-bit kernel dump
+func buildKernelDump(build uint32, processors uint32, code uint32, parameters [4]uint64, dumpType uint32) []byte {
+	data := make([]byte, 0x2000)
+
+	copy(data, "PAGEDU64")
+	binary.LittleEndian.PutUint32(data[0x8:], 0xF)
+	binary.LittleEndian.PutUint32(data[0xC:], build)
+	binary.LittleEndian.PutUint32(data[0x30:], 0x8664)
+	binary.LittleEndian.PutUint32(data[0x34:], processors)
+	binary.LittleEndian.PutUint32(data[0x38:], code)
+
+	for i, parameter := range parameters {
+		binary.LittleEndian.PutUint64(data[0x40+i*8:], parameter)
+	}
+
+	binary.LittleEndian.PutUint32(data[0xF98:], dumpType)
+
+	return data
+}
+
+// buildKernelDump32 generates the header of a 32-bit kernel dump
+func buildKernelDump32(build uint32, processors uint32, code uint32, parameters [4]uint32) []byte {
+	data := make([]byte, 0x1000)
+
+	copy(data, "PAGEDUMP")
+	binary.LittleEndian.PutUint32(data[0x8:], 0xF)
+	binary.LittleEndian.PutUint32(data[0xC:], build)
+	binary.LittleEndian.PutUint32(data[0x20:], 0x14C)
+	binary.LittleEndian.PutUint32(data[0x24:], processors)
+	binary.LittleEndian.PutUint32(data[0x28:], code)
+
+	for i, parameter := range parameters {
+		binary.LittleEndian.PutUint32(data[0x2C+i*4:], parameter)
+	}
+
+	binary.LittleEndian.PutUint32(data[0xF88:], 4)
+
+	return data
+}
+
+// appendDriverList appends the triage header and driver list of a small
+// memory dump to a kernel dump header
+func appendDriverList(header []byte, layout kernelDumpLayout, drivers []Module) []byte {
+	data := append(header, make([]byte, triageHeaderSize+len(drivers)*layout.driverEntrySize)...)
+	triage := data[layout.headerSize:]
+	listOffset := int(layout.headerSize) + triageHeaderSize
+
+	binary.LittleEndian.PutUint32(triage[triageValidOffsets:], 0x7F)
+	binary.LittleEndian.PutUint32(triage[triageDriverListOffset:], uint32(listOffset))
+	binary.LittleEndian.PutUint32(triage[triageDriverCount:], uint32(len(drivers)))
+
+	for i, driver := range drivers {
+		entry := data[listOffset+i*layout.driverEntrySize:]
+
+		binary.LittleEndian.PutUint32(entry, uint32(len(data)))
+		binary.LittleEndian.PutUint32(entry[layout.driverSize:], driver.Size)
+		binary.LittleEndian.PutUint32(entry[layout.driverTimeDateStamp:], driver.TimeDateStamp)
+
+		if layout.pointerSize == 8 {
+			binary.LittleEndian.PutUint64(entry[layout.driverBase:], driver.Base)
+		} else {
+			binary.LittleEndian.PutUint32(entry[layout.driverBase:], uint32(driver.Base))
+		}
+
+		name := utf16.Encode([]rune(driver.Name))
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(name)))
+
+		for _, unit := range append(name, 0) {
+			data = binary.LittleEndian.AppendUint16(data, unit)
+		}
+	}
+
+	return data
+}
+
+var testDrivers = []Module{
+	{Name: "ntoskrnl.exe", Base: 0xFFFFF80012000000, Size: 0x1046000, TimeDateStamp: 0x5F1E4E3A},
+	{Name: "nvlddmkm.sys", Base: 0xFFFFF80045600000, Size: 0x2F4C000, TimeDateStamp: 0x6543210F},
+}
+
+var testDrivers32 = []Module{
+	{Name: "ntoskrnl.exe", Base: 0x804D7000, Size: 0x1F8580, TimeDateStamp: 0x41108004},
+	{Name: "ati2mtag.sys", Base: 0xF7A1F000, Size: 0x101000, TimeDateStamp: 0x4B0C9A66},
+}
+
+var testModules = []Module{
+	{Name: `\SystemRoot\system32\ntoskrnl.exe`, Base: 0xFFFFF80012000000, Size: 0x1046000, TimeDateStamp: 0x5F1E4E3A},
+	{Name: `\SystemRoot\System32\drivers\nvlddmkm.sys`, Base: 0xFFFFF80045600000, Size: 0x2F4C000, TimeDateStamp: 0x6543210F},
+}
+
+var testException = &Exception{
+	ThreadID:           0x1F4,
+	Code:               0xC0000005,
+	Address:            0xFFFFF8004561A2B3,
+	Parameters:         []uint64{0, 0x10},
+	InstructionPointer: 0xFFFFF8004561A2B3,
+	StackPointer:       0xFFFFB80B2C3D4E50,
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected Dump
+	}{
+		{
+			name: "minidump",
+			data: buildMinidump(9, 8, 19045, testModules, nil),
+			expected: Dump{
+				Format:         FormatMinidump,
+				MajorVersion:   10,
+				BuildNumber:    19045,
+				ProcessorCount: 8,
+				Architecture:   "x64",
+				Modules:        testModules,
+			},
+		},
+		{
+			name: "minidump with an exception",
+			data: buildMinidump(9, 8, 19045, testModules, testException),
+			expected: Dump{
+				Format:         FormatMinidump,
+				MajorVersion:   10,
+				BuildNumber:    19045,
+				ProcessorCount: 8,
+				Architecture:   "x64",
+				Modules:        testModules,
+				Exception:      testException,
+			},
+		},
+		{
+			name: "x86 minidump with an exception",
+			data: buildMinidump(0, 2, 2600, nil, &Exception{ThreadID: 7, Code: 0x80000003, Address: 0x7C90120E, Parameters: []uint64{}, InstructionPointer: 0x7C90120F, StackPointer: 0x0012FFB0}),
+			expected: Dump{
+				Format:         FormatMinidump,
+				MajorVersion:   10,
+				BuildNumber:    2600,
+				ProcessorCount: 2,
+				Architecture:   "x86",
+				Modules:        []Module{},
+				Exception:      &Exception{ThreadID: 7, Code: 0x80000003, Address: 0x7C90120E, Parameters: []uint64{}, InstructionPointer: 0x7C90120F, StackPointer: 0x0012FFB0},
+			},
+		},
+		{
+			name: "minidump without modules",
+			data: buildMinidump(12, 4, 22631, nil, nil),
+			expected: Dump{
+				Format:         FormatMinidump,
+				MajorVersion:   10,
+				BuildNumber:    22631,
+				ProcessorCount: 4,
+				Architecture:   "ARM64",
+				Modules:        []Module{},
+			},
+		},
+		{
+			name: "64-bit kernel dump",
+			data: buildKernelDump(19041, 16, 0x9F, [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010}, 4),
+			expected: Dump{
+				Format:             FormatKernel64,
+				HasBugCheck:        true,
+				BugCheckCode:       0x9F,
+				BugCheckParameters: [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010},
+				BuildNumber:        19041,
+				ProcessorCount:     16,
+				Architecture:       "x64",
+				DumpType:           4,
+			},
+		},
+		{
+			name: "64-bit small memory dump with drivers",
+			data: appendDriverList(buildKernelDump(19045, 8, 0x7E, [4]uint64{0xFFFFFFFFC0000005, 0xFFFFF8004561A2B3, 0, 0}, 4), kernelDump64, testDrivers),
+			expected: Dump{
+				Format:             FormatKernel64,
+				HasBugCheck:        true,
+				BugCheckCode:       0x7E,
+				BugCheckParameters: [4]uint64{0xFFFFFFFFC0000005, 0xFFFFF8004561A2B3, 0, 0},
+				BuildNumber:        19045,
+				ProcessorCount:     8,
+				Architecture:       "x64",
+				DumpType:           4,
+				Modules:            testDrivers,
+			},
+		},
+		{
+			name: "64-bit kernel memory dump",
+			data: appendDriverList(buildKernelDump(19045, 8, 0x50, [4]uint64{}, 2), kernelDump64, testDrivers),
+			expected: Dump{
+				Format:         FormatKernel64,
+				HasBugCheck:    true,
+				BugCheckCode:   0x50,
+				BuildNumber:    19045,
+				ProcessorCount: 8,
+				Architecture:   "x64",
+				DumpType:       2,
+			},
+		},
+		{
+			name: "32-bit kernel dump",
+			data: buildKernelDump32(2600, 1, 0x7E, [4]uint32{0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740}),
+			expected: Dump{
+				Format:             FormatKernel32,
+				HasBugCheck:        true,
+				BugCheckCode:       0x7E,
+				BugCheckParameters: [4]uint64{0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740},
+				BuildNumber:        2600,
+				ProcessorCount:     1,
+				Architecture:       "x86",
+				DumpType:           4,
+			},
+		},
+		{
+			name: "32-bit small memory dump with drivers",
+			data: appendDriverList(buildKernelDump32(2600, 1, 0x7E, [4]uint32{0xC0000005, 0xF7A2B3C4, 0, 0}), kernelDump32, testDrivers32),
+			expected: Dump{
+				Format:             FormatKernel32,
+				HasBugCheck:        true,
+				BugCheckCode:       0x7E,
+				BugCheckParameters: [4]uint64{0xC0000005, 0xF7A2B3C4, 0, 0},
+				BuildNumber:        2600,
+				ProcessorCount:     1,
+				Architecture:       "x86",
+				DumpType:           4,
+				Modules:            testDrivers32,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(bytes.NewReader(tt.data))
+
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(*result, tt.expected) {
+				t.Errorf("Parse() = %+v, expected %+v", *result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	corruptModules := buildMinidump(9, 8, 19045, testModules, nil)
+	binary.LittleEndian.PutUint32(corruptModules[minidumpHeaderSize+2*directoryEntrySize+56:], 0xFFFFFFFF)
+
+	corruptException := buildMinidump(9, 8, 19045, nil, testException)
+	binary.LittleEndian.PutUint32(corruptException[len(corruptException)-contextLayouts["x64"].size-exceptionStreamSize+32:], 16)
+
+	corruptDrivers := appendDriverList(buildKernelDump(19045, 8, 0x7E, [4]uint64{}, 4), kernelDump64, testDrivers)
+	binary.LittleEndian.PutUint32(corruptDrivers[0x2000+triageDriverCount:], 0xFFFFFFFF)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{name: "empty", data: []byte{}, expected: ErrTruncated},
+		{name: "not a dump", data: []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00"), expected: ErrUnknownFormat},
+		{name: "PAGE without a known format", data: []byte("PAGEDU32"), expected: ErrUnknownFormat},
+		{name: "truncated kernel dump", data: buildKernelDump(19041, 16, 0x9F, [4]uint64{}, 4)[:0x100], expected: ErrTruncated},
+		{name: "truncated minidump", data: buildMinidump(9, 8, 19045, testModules, nil)[:200], expected: ErrTruncated},
+		{name: "too many modules", data: corruptModules, expected: ErrCorrupt},
+		{name: "too many drivers", data: corruptDrivers, expected: ErrCorrupt},
+		{name: "too many exception parameters", data: corruptException, expected: ErrCorrupt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(bytes.NewReader(tt.data))
+
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Parse() = %+v, %v, expected error %v", result, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDump_DumpTypeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		dumpType uint32
+		expected string
+	}{
+		{name: "small", dumpType: 4, expected: "Small memory dump"},
+		{name: "automatic", dumpType: 7, expected: "Automatic memory dump"},
+		{name: "unknown", dumpType: 42, expected: "Unknown dump type 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := (Dump{DumpType: tt.dumpType}).DumpTypeName(); result != tt.expected {
+				t.Errorf("DumpTypeName() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkParse_Minidump(b *testing.B) {
+	data := buildMinidump(9, 8, 19045, testModules, nil)
+
+	for i := 0; i < b.N; i++ {
+		Parse(bytes.NewReader(data))
+	}
+}
@@ -0,0 +1,89 @@
+package dump
+
+import "io"
+
+const (
+	exceptionStreamSize = 168
+	maxExceptionParams  = 15
+)
+
+// Exception is the exception a minidump was written for.
+type Exception struct {
+	ThreadID   uint32
+	Code       uint32
+	Address    uint64
+	Parameters []uint64
+	// InstructionPointer and StackPointer come from the context of the
+	// thread, they are zero if the dump has no context for the architecture
+	InstructionPointer uint64
+	StackPointer       uint64
+}
+
+// contextLayout locates the registers in the CONTEXT structure of an
+// architecture.
+type contextLayout struct {
+	size               int
+	instructionPointer int64
+	stackPointer       int64
+	pointerSize        int
+}
+
+var contextLayouts = map[string]contextLayout{
+	"x86":   {size: 0x2CC, instructionPointer: 0xB8, stackPointer: 0xC4, pointerSize: 4},
+	"x64":   {size: 0x4D0, instructionPointer: 0xF8, stackPointer: 0x98, pointerSize: 8},
+	"ARM64": {size: 0x390, instructionPointer: 0x108, stackPointer: 0x100, pointerSize: 8},
+}
+
+// readException reads the MINIDUMP_EXCEPTION_STREAM and the context of the
+// thread that raised the exception.
+func readException(r io.ReaderAt, stream stream, architecture string) (*Exception, error) {
+	data, err := readAt(r, int64(stream.rva), exceptionStreamSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exception := &Exception{
+		ThreadID: byteOrder.Uint32(data),
+		Code:     byteOrder.Uint32(data[8:]),
+		Address:  byteOrder.Uint64(data[24:]),
+	}
+
+	count := byteOrder.Uint32(data[32:])
+
+	if count > maxExceptionParams {
+		return nil, ErrCorrupt
+	}
+
+	exception.Parameters = make([]uint64, count)
+
+	for i := range exception.Parameters {
+		exception.Parameters[i] = byteOrder.Uint64(data[40+i*8:])
+	}
+
+	layout, ok := contextLayouts[architecture]
+	contextSize, contextRva := byteOrder.Uint32(data[160:]), byteOrder.Uint32(data[164:])
+
+	if !ok || contextSize < uint32(layout.size) {
+		return exception, nil
+	}
+
+	context, err := readAt(r, int64(contextRva), layout.size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	register := func(offset int64) uint64 {
+		if layout.pointerSize == 4 {
+			return uint64(byteOrder.Uint32(context[offset:]))
+		}
+
+		return byteOrder.Uint64(context[offset:])
+	}
+
+	exception.InstructionPointer = register(layout.instructionPointer)
+	exception.StackPointer = register(layout.stackPointer)
+
+	return exception, nil
+}
@@ -0,0 +1,202 @@
+package dump
+
+import (
+	"errors"
+	"io"
+)
+
+// smallMemoryDump is the dump type of the small memory dumps Windows saves
+// to C:\Windows\Minidump. Only they list the loaded drivers in their headers,
+// other kernel dumps keep the list in the memory they hold.
+const smallMemoryDump = 4
+
+// Fields of the TRIAGE_DUMP32 and TRIAGE_DUMP64 structures small memory
+// dumps have after their header. The offsets they hold are from the start of
+// the file.
+const (
+	triageValidOffsets     = 0x08
+	triageDriverListOffset = 0x30
+	triageDriverCount      = 0x34
+	triageHeaderSize       = 0x38
+	// triageDriverList is set in ValidOffsets if the dump has a driver list
+	triageDriverList    = 0x40
+	maxDriverNameLength = 4096
+)
+
+// kernelDumpLayout locates the fields of the DUMP_HEADER32 and
+// DUMP_HEADER64 structures kernel dumps start with, and of the
+// DUMP_DRIVER_ENTRY32 and DUMP_DRIVER_ENTRY64 structures in the driver list
+// of small memory dumps.
+type kernelDumpLayout struct {
+	format         Format
+	pointerSize    int
+	headerSize     int64
+	buildNumber    int64
+	machineType    int64
+	processorCount int64
+	bugCheckCode   int64
+	parameters     int64
+	dumpType       int64
+
+	driverEntrySize     int
+	driverBase          int
+	driverSize          int
+	driverTimeDateStamp int
+}
+
+var kernelDump64 = kernelDumpLayout{
+	format:         FormatKernel64,
+	pointerSize:    8,
+	headerSize:     0x2000,
+	buildNumber:    0x00C,
+	machineType:    0x030,
+	processorCount: 0x034,
+	bugCheckCode:   0x038,
+	parameters:     0x040,
+	dumpType:       0xF98,
+
+	driverEntrySize:     0x90,
+	driverBase:          0x38,
+	driverSize:          0x48,
+	driverTimeDateStamp: 0x88,
+}
+
+var kernelDump32 = kernelDumpLayout{
+	format:         FormatKernel32,
+	pointerSize:    4,
+	headerSize:     0x1000,
+	buildNumber:    0x00C,
+	machineType:    0x020,
+	processorCount: 0x024,
+	bugCheckCode:   0x028,
+	parameters:     0x02C,
+	dumpType:       0xF88,
+
+	driverEntrySize:     0x4C,
+	driverBase:          0x1C,
+	driverSize:          0x24,
+	driverTimeDateStamp: 0x48,
+}
+
+var machineTypes = map[uint32]string{
+	0x014C: "x86",
+	0x01C4: "ARM",
+	0x8664: "x64",
+	0xAA64: "ARM64",
+}
+
+func parseKernelDump(r io.ReaderAt, layout kernelDumpLayout) (*Dump, error) {
+	header, err := readAt(r, 0, int(layout.dumpType)+4)
+
+	if err != nil {
+		return nil, err
+	}
+
+	field := func(offset int64) uint32 {
+		return byteOrder.Uint32(header[offset:])
+	}
+
+	dump := &Dump{
+		Format:         layout.format,
+		HasBugCheck:    true,
+		BugCheckCode:   field(layout.bugCheckCode),
+		BuildNumber:    field(layout.buildNumber),
+		ProcessorCount: field(layout.processorCount),
+		Architecture:   machineTypes[field(layout.machineType)],
+		DumpType:       field(layout.dumpType),
+	}
+
+	for i := range dump.BugCheckParameters {
+		offset := layout.parameters + int64(i*layout.pointerSize)
+
+		if layout.pointerSize == 8 {
+			dump.BugCheckParameters[i] = byteOrder.Uint64(header[offset:])
+		} else {
+			dump.BugCheckParameters[i] = uint64(field(offset))
+		}
+	}
+
+	if dump.DumpType == smallMemoryDump {
+		dump.Modules, err = readDriverList(r, layout)
+
+		// the bug check is still worth reporting if the download cut the
+		// dump off after its header
+		if err != nil && !errors.Is(err, ErrTruncated) {
+			return nil, err
+		}
+	}
+
+	return dump, nil
+}
+
+// readDriverList reads the drivers that were loaded when a small memory
+// dump was written.
+func readDriverList(r io.ReaderAt, layout kernelDumpLayout) ([]Module, error) {
+	triage, err := readAt(r, layout.headerSize, triageHeaderSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if byteOrder.Uint32(triage[triageValidOffsets:])&triageDriverList == 0 {
+		return nil, nil
+	}
+
+	count := byteOrder.Uint32(triage[triageDriverCount:])
+
+	if count > maxModules {
+		return nil, ErrCorrupt
+	}
+
+	entries, err := readAt(r, int64(byteOrder.Uint32(triage[triageDriverListOffset:])), int(count)*layout.driverEntrySize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]Module, count)
+
+	for i := range modules {
+		entry := entries[i*layout.driverEntrySize:]
+
+		name, err := readDriverName(r, byteOrder.Uint32(entry))
+
+		if err != nil {
+			return nil, err
+		}
+
+		modules[i] = Module{
+			Name:          name,
+			TimeDateStamp: byteOrder.Uint32(entry[layout.driverTimeDateStamp:]),
+			Size:          byteOrder.Uint32(entry[layout.driverSize:]),
+		}
+
+		if layout.pointerSize == 8 {
+			modules[i].Base = byteOrder.Uint64(entry[layout.driverBase:])
+		} else {
+			modules[i].Base = uint64(byteOrder.Uint32(entry[layout.driverBase:]))
+		}
+	}
+
+	return modules, nil
+}
+
+// readDriverName reads a DUMP_STRING, a UTF-16 string prefixed with its
+// length in characters.
+func readDriverName(r io.ReaderAt, offset uint32) (string, error) {
+	header, err := readAt(r, int64(offset), 4)
+
+	if err != nil {
+		return "", err
+	}
+
+	length := min(byteOrder.Uint32(header), maxDriverNameLength)
+
+	data, err := readAt(r, int64(offset)+4, int(length)*2)
+
+	if err != nil {
+		return "", err
+	}
+
+	return decodeUTF16(data), nil
+}
@@ -0,0 +1,197 @@
+package dump
+
+import (
+	"io"
+	"unicode/utf16"
+)
+
+// Stream types of the minidump stream directory
+const (
+	moduleListStream = 4
+	exceptionStream  = 6
+	systemInfoStream = 7
+)
+
+const (
+	minidumpHeaderSize  = 32
+	directoryEntrySize  = 12
+	moduleEntrySize     = 108
+	systemInfoSize      = 24
+	maxStreams          = 4096
+	maxModules          = 65536
+	maxModuleNameLength = 4096
+)
+
+var processorArchitectures = map[uint16]string{
+	0:  "x86",
+	5:  "ARM",
+	6:  "IA64",
+	9:  "x64",
+	12: "ARM64",
+}
+
+// stream is an entry of the minidump stream directory.
+type stream struct {
+	streamType uint32
+	size       uint32
+	rva        uint32
+}
+
+func parseMinidump(r io.ReaderAt) (*Dump, error) {
+	header, err := readAt(r, 0, minidumpHeaderSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	streams, err := readStreamDirectory(r, byteOrder.Uint32(header[8:]), byteOrder.Uint32(header[12:]))
+
+	if err != nil {
+		return nil, err
+	}
+
+	dump := &Dump{Format: FormatMinidump}
+
+	var exception *stream
+
+	for _, stream := range streams {
+		switch stream.streamType {
+		case systemInfoStream:
+			err = readSystemInfo(r, stream, dump)
+		case moduleListStream:
+			dump.Modules, err = readModuleList(r, stream)
+		case exceptionStream:
+			exception = &stream
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the layout of the context depends on the architecture from the system
+	// info stream, which may come later in the directory
+	if exception != nil {
+		if dump.Exception, err = readException(r, *exception, dump.Architecture); err != nil {
+			return nil, err
+		}
+	}
+
+	return dump, nil
+}
+
+func readStreamDirectory(r io.ReaderAt, count uint32, rva uint32) ([]stream, error) {
+	if count > maxStreams {
+		return nil, ErrCorrupt
+	}
+
+	directory, err := readAt(r, int64(rva), int(count)*directoryEntrySize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	streams := make([]stream, count)
+
+	for i := range streams {
+		entry := directory[i*directoryEntrySize:]
+
+		streams[i] = stream{
+			streamType: byteOrder.Uint32(entry),
+			size:       byteOrder.Uint32(entry[4:]),
+			rva:        byteOrder.Uint32(entry[8:]),
+		}
+	}
+
+	return streams, nil
+}
+
+// readSystemInfo reads the MINIDUMP_SYSTEM_INFO stream.
+func readSystemInfo(r io.ReaderAt, stream stream, dump *Dump) error {
+	info, err := readAt(r, int64(stream.rva), systemInfoSize)
+
+	if err != nil {
+		return err
+	}
+
+	dump.Architecture = processorArchitectures[byteOrder.Uint16(info)]
+	dump.ProcessorCount = uint32(info[6])
+	dump.MajorVersion = byteOrder.Uint32(info[8:])
+	dump.MinorVersion = byteOrder.Uint32(info[12:])
+	dump.BuildNumber = byteOrder.Uint32(info[16:])
+
+	return nil
+}
+
+// readModuleList reads the MINIDUMP_MODULE_LIST stream.
+func readModuleList(r io.ReaderAt, stream stream) ([]Module, error) {
+	header, err := readAt(r, int64(stream.rva), 4)
+
+	if err != nil {
+		return nil, err
+	}
+
+	count := byteOrder.Uint32(header)
+
+	if count > maxModules {
+		return nil, ErrCorrupt
+	}
+
+	entries, err := readAt(r, int64(stream.rva)+4, int(count)*moduleEntrySize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]Module, count)
+
+	for i := range modules {
+		entry := entries[i*moduleEntrySize:]
+
+		name, err := readString(r, byteOrder.Uint32(entry[20:]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		modules[i] = Module{
+			Name:          name,
+			Base:          byteOrder.Uint64(entry),
+			Size:          byteOrder.Uint32(entry[8:]),
+			TimeDateStamp: byteOrder.Uint32(entry[16:]),
+		}
+	}
+
+	return modules, nil
+}
+
+// readString reads a MINIDUMP_STRING, a UTF-16 string prefixed with its
+// length in bytes.
+func readString(r io.ReaderAt, rva uint32) (string, error) {
+	header, err := readAt(r, int64(rva), 4)
+
+	if err != nil {
+		return "", err
+	}
+
+	length := min(byteOrder.Uint32(header), maxModuleNameLength) / 2 * 2
+
+	data, err := readAt(r, int64(rva)+4, int(length))
+
+	if err != nil {
+		return "", err
+	}
+
+	return decodeUTF16(data), nil
+}
+
+// decodeUTF16 decodes a little-endian UTF-16 string.
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, len(data)/2)
+
+	for i := range units {
+		units[i] = byteOrder.Uint16(data[i*2:])
+	}
+
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,73 @@
+package dump
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BaseName returns the file name of the module without its path.
+func (module Module) BaseName() string {
+	return module.Name[strings.LastIndexAny(module.Name, `\/`)+1:]
+}
+
+// ModuleAddress is an address inside a module.
+type ModuleAddress struct {
+	Module Module
+	Offset uint64
+}
+
+// String formats the address like the debugger, e.g. nvlddmkm.sys+0x1A2B3.
+func (address ModuleAddress) String() string {
+	return fmt.Sprintf("%s+0x%X", address.Module.BaseName(), address.Offset)
+}
+
+// ModuleMap finds the modules addresses fall into.
+type ModuleMap struct {
+	// modules are sorted by their base address
+	modules []Module
+}
+
+// NewModuleMap creates a map of the address ranges of modules. Modules
+// without a size are left out.
+func NewModuleMap(modules []Module) ModuleMap {
+	result := ModuleMap{}
+
+	for _, module := range modules {
+		if module.Size > 0 {
+			result.modules = append(result.modules, module)
+		}
+	}
+
+	slices.SortFunc(result.modules, func(a, b Module) int {
+		return cmp.Compare(a.Base, b.Base)
+	})
+
+	return result
+}
+
+// Resolve finds the module an address falls inside.
+func (m ModuleMap) Resolve(address uint64) (ModuleAddress, bool) {
+	// the first module that starts after the address
+	index, _ := slices.BinarySearchFunc(m.modules, address, func(module Module, address uint64) int {
+		if module.Base <= address {
+			return -1
+		}
+
+		return 1
+	})
+
+	if index == 0 {
+		return ModuleAddress{}, false
+	}
+
+	module := m.modules[index-1]
+	offset := address - module.Base
+
+	if offset >= uint64(module.Size) {
+		return ModuleAddress{}, false
+	}
+
+	return ModuleAddress{Module: module, Offset: offset}, true
+}
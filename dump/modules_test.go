@@ -0,0 +1,83 @@
+package dump
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestModuleMap_Resolve(t *testing.T) {
+	modules := NewModuleMap(append([]Module{
+		{Name: `\SystemRoot\System32\drivers\empty.sys`, Base: 0xFFFFF80045000000, Size: 0},
+		{Name: `C:\Windows\System32\drivers\dxgkrnl.sys`, Base: 0xFFFFF80044000000, Size: 0x1000},
+	}, testModules...))
+
+	tests := []struct {
+		name     string
+		address  uint64
+		expected string
+	}{
+		{name: "inside a driver", address: 0xFFFFF8004561A2B3, expected: "nvlddmkm.sys+0x1A2B3"},
+		{name: "module base", address: 0xFFFFF80012000000, expected: "ntoskrnl.exe+0x0"},
+		{name: "last byte", address: 0xFFFFF80044000FFF, expected: "dxgkrnl.sys+0xFFF"},
+		{name: "past the end", address: 0xFFFFF80044001000, expected: ""},
+		{name: "module without a size", address: 0xFFFFF80045000000, expected: ""},
+		{name: "below all modules", address: 0x3, expected: ""},
+		{name: "above all modules", address: 0xFFFFFFFFFFFFFFFF, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := modules.Resolve(tt.address)
+
+			if ok != (tt.expected != "") {
+				t.Fatalf("Resolve(0x%X) found = %v, expected %v", tt.address, ok, !ok)
+			}
+
+			if ok && result.String() != tt.expected {
+				t.Errorf("Resolve(0x%X) = %s, expected %s", tt.address, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModuleMap_ResolveException(t *testing.T) {
+	result, err := Parse(bytes.NewReader(buildMinidump(9, 8, 19045, testModules, testException)))
+
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	address, ok := NewModuleMap(result.Modules).Resolve(result.Exception.InstructionPointer)
+
+	if !ok || address.String() != "nvlddmkm.sys+0x1A2B3" {
+		t.Errorf("Resolve() = %s, %v, expected nvlddmkm.sys+0x1A2B3", address, ok)
+	}
+}
+
+func TestModule_BaseName(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "NT path", path: `\SystemRoot\System32\drivers\nvlddmkm.sys`, expected: "nvlddmkm.sys"},
+		{name: "DOS path", path: `C:\Windows\System32\ntdll.dll`, expected: "ntdll.dll"},
+		{name: "no path", path: "hal.dll", expected: "hal.dll"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := (Module{Name: tt.path}).BaseName(); result != tt.expected {
+				t.Errorf("BaseName() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkModuleMap_Resolve(b *testing.B) {
+	modules := NewModuleMap(testModules)
+
+	for i := 0; i < b.N; i++ {
+		modules.Resolve(0xFFFFF8004561A2B3)
+	}
+}
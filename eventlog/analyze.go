@@ -0,0 +1,302 @@
+package eventlog
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dhrdlicka/errorbot/repo"
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+// Report lists the error codes found in an event.
+type Report struct {
+	Event Event
+	// BugCheck is set for events that record a bug check, such as
+	// Kernel-Power 41 and the 1001 event of WER-SystemErrorReporting
+	BugCheck *BugCheck
+	Findings []Finding
+}
+
+// BugCheck is a bug check recorded by an event.
+type BugCheck struct {
+	Code uint32
+	// Name is empty if the bug check is not in the catalog
+	Name       string
+	Parameters []repo.BugCheckParameter
+}
+
+// Finding is an error code found in a field of an event.
+type Finding struct {
+	// Field is the name of the field, or "Data N" for unnamed fields
+	Field string
+	// Text is the code as it was written in the field
+	Text string
+	Code uint32
+	// Matches is empty if the field holds an unknown code
+	Matches []Match
+}
+
+// Match is a catalog entry a code may be.
+type Match struct {
+	repo.ErrorInfo
+	Kind repo.Kind
+}
+
+// errorFieldKinds maps the names of fields that hold error codes, lower-case
+// without separators, to the catalogs the codes are most likely from.
+var errorFieldKinds = map[string][]repo.Kind{
+	"status":        {repo.KindNTStatus, repo.KindHResult},
+	"ntstatus":      {repo.KindNTStatus},
+	"statuscode":    {repo.KindNTStatus, repo.KindHResult},
+	"exitstatus":    {repo.KindNTStatus, repo.KindWin32Error},
+	"finalstatus":   {repo.KindNTStatus, repo.KindHResult},
+	"exceptioncode": {repo.KindNTStatus, repo.KindHResult},
+	"hresult":       {repo.KindHResult},
+	"hr":            {repo.KindHResult},
+	"errorcode":     {repo.KindWin32Error, repo.KindHResult},
+	"error":         {repo.KindWin32Error, repo.KindHResult},
+	"win32error":    {repo.KindWin32Error},
+	"lasterror":     {repo.KindWin32Error},
+	"exitcode":      {repo.KindWin32Error, repo.KindNTStatus},
+	"returncode":    {repo.KindWin32Error, repo.KindHResult},
+	"resultcode":    {repo.KindHResult, repo.KindWin32Error},
+}
+
+// Analyze finds the error codes in the fields of an event and looks them up.
+//
+// Fields named like Status, ErrorCode or HResult are read as codes from the
+// catalogs their names hint at and are reported even if the code is unknown.
+// BugcheckCode and BugcheckParameter1 to 4 make up a bug check. Other
+// fields, such as param1 or unnamed ones, are searched for bug check lines
+// and anything that looks like an error code, which is only reported if it
+// is known.
+func Analyze(r repo.Repo, event Event) Report {
+	report := Report{Event: event}
+
+	var bugCheckCode uint32
+	var bugCheckParameters [4]uint64
+
+	for i, data := range event.Data {
+		field := data.Name
+
+		if field == "" {
+			field = fmt.Sprintf("Data %d", i+1)
+		}
+
+		key := fieldKey(data.Name)
+
+		if key == "bugcheckcode" {
+			bugCheckCode, _ = parseFieldValue(data.Value)
+			continue
+		}
+
+		if index, ok := strings.CutPrefix(key, "bugcheckparameter"); ok {
+			if n, err := strconv.Atoi(index); err == nil && n >= 1 && n <= 4 {
+				bugCheckParameters[n-1], _ = parseParameterValue(data.Value)
+				continue
+			}
+		}
+
+		if kinds, ok := errorFieldKinds[key]; ok {
+			if finding, ok := findCode(r, field, data.Value, kinds); ok {
+				report.Findings = append(report.Findings, finding)
+			}
+
+			continue
+		}
+
+		// the 1001 events put the whole bug check into param1
+		if report.BugCheck == nil && bugCheckCode == 0 {
+			if line, err := util.ParseBugCheckLine(data.Value); err == nil && line.Format == "STOP line" {
+				report.BugCheck = newBugCheck(r, line.Code, line.Parameters)
+				continue
+			}
+		}
+
+		report.Findings = append(report.Findings, extractCodes(r, field, data.Value)...)
+	}
+
+	// Kernel-Power 41 has a zero code when the system did not bug check
+	if bugCheckCode != 0 {
+		report.BugCheck = newBugCheck(r, bugCheckCode, bugCheckParameters)
+	}
+
+	return report
+}
+
+// HasCodes reports whether anything was found in the event.
+func (report Report) HasCodes() bool {
+	return report.BugCheck != nil || len(report.Findings) > 0
+}
+
+func newBugCheck(r repo.Repo, code uint32, parameters [4]uint64) *BugCheck {
+	bugCheck := &BugCheck{
+		Code:       code,
+		Parameters: r.DecodeBugCheck(code, parameters),
+	}
+
+	if matches := r.FindBugCheckCode(code); len(matches) > 0 {
+		bugCheck.Name = matches[0].Name
+	}
+
+	return bugCheck
+}
+
+// findCode reads the value of a field known to hold an error code. Zero
+// means success and is left out.
+func findCode(r repo.Repo, field string, value string, kinds []repo.Kind) (Finding, bool) {
+	// insertion strings like %%1053 refer to Win32 error messages
+	if digits, ok := strings.CutPrefix(value, "%%"); ok {
+		value, kinds = digits, []repo.Kind{repo.KindWin32Error}
+	}
+
+	code, ok := parseFieldValue(value)
+
+	if !ok || code == 0 {
+		return Finding{}, false
+	}
+
+	return Finding{
+		Field:   field,
+		Text:    value,
+		Code:    code,
+		Matches: findMatches(r, code, kinds),
+	}, true
+}
+
+// extractCodes finds known error codes in a field that may hold anything.
+func extractCodes(r repo.Repo, field string, value string) []Finding {
+	if strings.HasPrefix(value, "%%") {
+		if finding, ok := findCode(r, field, value, nil); ok && len(finding.Matches) > 0 {
+			return []Finding{finding}
+		}
+
+		return nil
+	}
+
+	findings := []Finding{}
+
+	for _, match := range util.ExtractCodes(value) {
+		finding := Finding{Field: field, Text: match.Text}
+
+		if match.IsName() {
+			for _, result := range r.FindByName(match.Text, match.Kinds...) {
+				if strings.EqualFold(result.Name, match.Text) {
+					finding.Code = result.Code
+					finding.Matches = append(finding.Matches, Match{result.ErrorInfo, result.Kind})
+				}
+			}
+		} else {
+			finding.Code = match.Codes[0]
+			finding.Matches = findMatches(r, match.Codes[0], match.Kinds)
+		}
+
+		if len(finding.Matches) > 0 {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// findMatches looks a code up in the catalogs the field hints at, or in all
+// catalogs but the bug checks if it is in none of them.
+func findMatches(r repo.Repo, code uint32, kinds []repo.Kind) []Match {
+	matches := []Match{}
+
+	for _, kind := range kinds {
+		for _, info := range r.Find(kind, code) {
+			matches = append(matches, Match{info, kind})
+		}
+	}
+
+	if len(matches) > 0 {
+		return matches
+	}
+
+	for _, kind := range repo.Kinds {
+		if kind == repo.KindBugCheck || slices.Contains(kinds, kind) {
+			continue
+		}
+
+		for _, info := range r.Find(kind, code) {
+			matches = append(matches, Match{info, kind})
+		}
+	}
+
+	return matches
+}
+
+// fieldKey normalizes a field name so that ErrorCode, errorCode and
+// error_code are the same.
+func fieldKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(name))
+}
+
+// parseFieldValue reads a code from a field. Events hold decimal numbers
+// unless they have a 0x prefix, except for the bare hex exception codes of
+// Application Error events such as c0000005. Negative and sign-extended
+// 64-bit values are accepted.
+func parseFieldValue(value string) (uint32, bool) {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+
+	var number int64
+
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		parsed, err := strconv.ParseUint(value[2:], 16, 64)
+
+		if err != nil {
+			return 0, false
+		}
+
+		number = int64(parsed)
+	case len(value) == 8 && strings.Trim(lower, "0123456789abcdef") == "" && strings.ContainsAny(lower, "abcdef"):
+		parsed, err := strconv.ParseUint(value, 16, 32)
+
+		if err != nil {
+			return 0, false
+		}
+
+		number = int64(parsed)
+	default:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+
+		if err != nil {
+			return 0, false
+		}
+
+		number = parsed
+	}
+
+	if number < math.MinInt32 || number > math.MaxUint32 {
+		return 0, false
+	}
+
+	return uint32(number), true
+}
+
+// parseParameterValue reads a bug check parameter from a field, which is
+// decimal unless it has a 0x prefix.
+func parseParameterValue(value string) (uint64, bool) {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(strings.ToLower(value), "0x") {
+		parameter, err := util.ParseParameter(value)
+		return parameter, err == nil
+	}
+
+	parameter, err := strconv.ParseUint(value, 10, 64)
+
+	return parameter, err == nil
+}
@@ -0,0 +1,234 @@
+package eventlog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+func createTestRepo() repo.Repo {
+	testRepo := repo.New(
+		repo.NewBugCheckCatalog("bug check", repo.BugCheckRepo{
+			{Code: 0x9F, Name: "DRIVER_POWER_STATE_FAILURE", Parameters: []string{"Subtype"}},
+		}),
+		repo.NewListCatalog("HRESULT", repo.KindHResult, []repo.ErrorInfo{
+			{Code: 0x80004005, Name: "E_FAIL", Description: "Unspecified error"},
+		}, nil),
+		repo.NewListCatalog("Win32 error", repo.KindWin32Error, []repo.ErrorInfo{
+			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
+			{Code: 1053, Name: "ERROR_SERVICE_REQUEST_TIMEOUT", Description: "The service did not respond to the start or control request in a timely fashion."},
+		}, nil),
+		repo.NewListCatalog("NTSTATUS", repo.KindNTStatus, []repo.ErrorInfo{
+			{Code: 0xC0000005, Name: "STATUS_ACCESS_VIOLATION", Description: "The instruction referenced memory it could not access."},
+			{Code: 0xC0000034, Name: "STATUS_OBJECT_NAME_NOT_FOUND", Description: "Object Name not found."},
+		}, nil),
+	)
+
+	return testRepo
+}
+
+// findingNames summarizes findings as field=name pairs
+func findingNames(findings []Finding) []string {
+	result := []string{}
+
+	for _, finding := range findings {
+		names := []string{}
+
+		for _, match := range finding.Matches {
+			names = append(names, match.Name)
+		}
+
+		if len(names) == 0 {
+			names = append(names, "unknown")
+		}
+
+		result = append(result, finding.Field+"="+strings.Join(names, ","))
+	}
+
+	return result
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []Data
+		expected []string
+	}{
+		{
+			name:     "Win32 insertion string",
+			data:     []Data{{Name: "param1", Value: "Contoso Updater"}, {Name: "param2", Value: "%%1053"}},
+			expected: []string{"param2=ERROR_SERVICE_REQUEST_TIMEOUT"},
+		},
+		{
+			name:     "bare exception code",
+			data:     []Data{{Value: "game.exe"}, {Value: "1.0.0.0"}, {Value: "c0000005"}},
+			expected: []string{"Data 3=STATUS_ACCESS_VIOLATION"},
+		},
+		{
+			name:     "named HRESULT field",
+			data:     []Data{{Name: "errorCode", Value: "0x80070005"}},
+			expected: []string{"errorCode=HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)"},
+		},
+		{
+			name:     "result field",
+			data:     []Data{{Name: "ResultCode", Value: "0x80004005"}},
+			expected: []string{"ResultCode=E_FAIL"},
+		},
+		{
+			name:     "decimal Win32 error",
+			data:     []Data{{Name: "ErrorCode", Value: "5"}},
+			expected: []string{"ErrorCode=ERROR_ACCESS_DENIED"},
+		},
+		{
+			name:     "signed status",
+			data:     []Data{{Name: "Status", Value: "-1073741772"}},
+			expected: []string{"Status=STATUS_OBJECT_NAME_NOT_FOUND"},
+		},
+		{
+			name:     "sign-extended status",
+			data:     []Data{{Name: "Status", Value: "0xFFFFFFFFC0000005"}},
+			expected: []string{"Status=STATUS_ACCESS_VIOLATION"},
+		},
+		{
+			name:     "unknown code in an error field",
+			data:     []Data{{Name: "HResult", Value: "0x80AB0001"}},
+			expected: []string{"HResult=unknown"},
+		},
+		{
+			name:     "success",
+			data:     []Data{{Name: "Status", Value: "0x0"}, {Name: "ErrorCode", Value: "0"}},
+			expected: []string{},
+		},
+		{
+			name:     "symbolic name in text",
+			data:     []Data{{Name: "Message", Value: "Opening the key failed with STATUS_ACCESS_VIOLATION"}},
+			expected: []string{"Message=STATUS_ACCESS_VIOLATION"},
+		},
+		{
+			name:     "text without codes",
+			data:     []Data{{Name: "param1", Value: "Contoso Updater"}, {Name: "param2", Value: "running"}},
+			expected: []string{},
+		},
+	}
+
+	testRepo := createTestRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Analyze(testRepo, Event{Data: tt.data})
+
+			if result := findingNames(report.Findings); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Analyze() found %v, expected %v", result, tt.expected)
+			}
+
+			if report.BugCheck != nil {
+				t.Errorf("Analyze() found bug check 0x%X", report.BugCheck.Code)
+			}
+		})
+	}
+}
+
+func TestAnalyze_BugCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []Data
+		code       uint32
+		bugCheck   string
+		parameters [4]uint64
+	}{
+		{
+			name: "Kernel-Power 41",
+			data: []Data{
+				{Name: "BugcheckCode", Value: "159"},
+				{Name: "BugcheckParameter1", Value: "0x3"},
+				{Name: "BugcheckParameter2", Value: "0xffffe0012ad7e880"},
+				{Name: "BugcheckParameter3", Value: "0xfffff8024b9b1960"},
+				{Name: "BugcheckParameter4", Value: "0xffffe0012e2d0010"},
+				{Name: "SleepInProgress", Value: "0"},
+			},
+			code:       0x9F,
+			bugCheck:   "DRIVER_POWER_STATE_FAILURE",
+			parameters: [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010},
+		},
+		{
+			name: "WER 1001",
+			data: []Data{
+				{Name: "param1", Value: "0x0000009f (0x0000000000000003, 0xffffe0012ad7e880, 0xfffff8024b9b1960, 0xffffe0012e2d0010)"},
+				{Name: "param2", Value: `C:\Windows\Minidump\030524-12345-01.dmp`},
+				{Name: "param3", Value: "7d3f5a7c-0d1b-4a5f-9a0c-3b1c2d4e5f60"},
+			},
+			code:       0x9F,
+			bugCheck:   "DRIVER_POWER_STATE_FAILURE",
+			parameters: [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010},
+		},
+		{
+			name:       "unknown bug check",
+			data:       []Data{{Name: "BugcheckCode", Value: "0x1234"}, {Name: "BugcheckParameter1", Value: "7"}},
+			code:       0x1234,
+			parameters: [4]uint64{7},
+		},
+	}
+
+	testRepo := createTestRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Analyze(testRepo, Event{Data: tt.data})
+
+			if report.BugCheck == nil {
+				t.Fatalf("Analyze() found no bug check")
+			}
+
+			if report.BugCheck.Code != tt.code || report.BugCheck.Name != tt.bugCheck {
+				t.Errorf("Analyze() found bug check %s (0x%X), expected %s (0x%X)", report.BugCheck.Name, report.BugCheck.Code, tt.bugCheck, tt.code)
+			}
+
+			for i, parameter := range report.BugCheck.Parameters {
+				if parameter.Value != tt.parameters[i] {
+					t.Errorf("parameter %d = 0x%X, expected 0x%X", i+1, parameter.Value, tt.parameters[i])
+				}
+			}
+
+			if len(report.Findings) != 0 {
+				t.Errorf("Analyze() also found %v", findingNames(report.Findings))
+			}
+		})
+	}
+}
+
+func TestAnalyze_NoBugCheck(t *testing.T) {
+	report := Analyze(createTestRepo(), Event{Data: []Data{{Name: "BugcheckCode", Value: "0"}, {Name: "BugcheckParameter1", Value: "0x0"}}})
+
+	if report.HasCodes() {
+		t.Errorf("Analyze() = %+v, expected nothing for a power loss without a bug check", report)
+	}
+}
+
+func TestParseFieldValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected uint32
+		ok       bool
+	}{
+		{name: "hex", value: "0xC0000005", expected: 0xC0000005, ok: true},
+		{name: "decimal", value: "159", expected: 0x9F, ok: true},
+		{name: "negative", value: "-2147024891", expected: 0x80070005, ok: true},
+		{name: "bare hex", value: "c0000005", expected: 0xC0000005, ok: true},
+		{name: "sign-extended", value: "0xffffffff80070005", expected: 0x80070005, ok: true},
+		{name: "too large", value: "0x100000000", ok: false},
+		{name: "text", value: "running", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := parseFieldValue(tt.value)
+
+			if ok != tt.ok || result != tt.expected {
+				t.Errorf("parseFieldValue(%q) = 0x%X, %v, expected 0x%X, %v", tt.value, result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
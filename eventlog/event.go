@@ -0,0 +1,195 @@
+// Package eventlog reads events exported from the Windows event log as XML,
+// either saved from Event Viewer or printed by wevtutil qe /f:xml, and finds
+// the error codes in their fields.
+package eventlog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+// ErrNoEvents is returned for XML documents without any events.
+var ErrNoEvents = errors.New("no events found")
+
+// Event is an event from the event log.
+type Event struct {
+	Provider    string
+	EventID     uint32
+	Level       uint8
+	TimeCreated time.Time
+	RecordID    uint64
+	Channel     string
+	Computer    string
+	// Message is the rendered message, Event Viewer only includes it in
+	// some exports
+	Message string
+	// Data holds the fields of EventData followed by the fields of
+	// UserData
+	Data []Data
+}
+
+// Data is a field of an event. Fields of classic events often have no name.
+type Data struct {
+	Name  string
+	Value string
+}
+
+// LevelName names the level of the event like Event Viewer does.
+func (event Event) LevelName() string {
+	switch event.Level {
+	case 0:
+		return "Information"
+	case 1:
+		return "Critical"
+	case 2:
+		return "Error"
+	case 3:
+		return "Warning"
+	case 4:
+		return "Information"
+	case 5:
+		return "Verbose"
+	}
+
+	return fmt.Sprintf("Level %d", event.Level)
+}
+
+type xmlEvent struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID     string `xml:"EventID"`
+		Level       uint8  `xml:"Level"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		EventRecordID uint64 `xml:"EventRecordID"`
+		Channel       string `xml:"Channel"`
+		Computer      string `xml:"Computer"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+	} `xml:"EventData"`
+	UserData      xmlNode `xml:"UserData"`
+	RenderingInfo struct {
+		Message string `xml:"Message"`
+	} `xml:"RenderingInfo"`
+}
+
+// xmlNode is an element of UserData, whose structure is up to the provider.
+type xmlNode struct {
+	XMLName  xml.Name
+	Content  string    `xml:",chardata"`
+	Children []xmlNode `xml:",any"`
+}
+
+// Parse reads the events from an XML document. Event Viewer wraps them in an
+// Events element, wevtutil prints them one after another without one; both
+// are accepted, in UTF-8 or in UTF-16 with a byte order mark. Events read
+// before a syntax error are returned along with it.
+func Parse(r io.Reader) ([]Event, error) {
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(util.DecodeText(data)))
+	// UTF-16 documents are converted to UTF-8 by now, but still say they
+	// are UTF-16 in their declaration
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if !strings.HasPrefix(strings.ToLower(charset), "utf-16") {
+			return nil, fmt.Errorf("unsupported encoding %s", charset)
+		}
+
+		return input, nil
+	}
+
+	events := []Event{}
+
+	for {
+		token, err := decoder.Token()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return events, fmt.Errorf("invalid event XML: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+
+		if !ok || start.Name.Local != "Event" {
+			continue
+		}
+
+		var element xmlEvent
+
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return events, fmt.Errorf("invalid event XML: %w", err)
+		}
+
+		events = append(events, element.event())
+	}
+
+	if len(events) == 0 {
+		return nil, ErrNoEvents
+	}
+
+	return events, nil
+}
+
+func (element xmlEvent) event() Event {
+	system := element.System
+
+	event := Event{
+		Provider: system.Provider.Name,
+		Level:    system.Level,
+		RecordID: system.EventRecordID,
+		Channel:  strings.TrimSpace(system.Channel),
+		Computer: strings.TrimSpace(system.Computer),
+		Message:  strings.TrimSpace(element.RenderingInfo.Message),
+	}
+
+	// the ID is not a plain number in some providers' manifests, keep
+	// what can be read
+	fmt.Sscan(system.EventID, &event.EventID)
+
+	if created, err := time.Parse(time.RFC3339Nano, system.TimeCreated.SystemTime); err == nil {
+		event.TimeCreated = created
+	}
+
+	for _, data := range element.EventData.Data {
+		event.Data = append(event.Data, Data{Name: data.Name, Value: strings.TrimSpace(data.Value)})
+	}
+
+	event.Data = append(event.Data, element.UserData.leaves()...)
+
+	return event
+}
+
+// leaves flattens the elements below the node into fields named after the
+// elements that hold the values.
+func (node xmlNode) leaves() []Data {
+	data := []Data{}
+
+	for _, child := range node.Children {
+		if len(child.Children) == 0 {
+			data = append(data, Data{Name: child.XMLName.Local, Value: strings.TrimSpace(child.Content)})
+		} else {
+			data = append(data, child.leaves()...)
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,233 @@
+package eventlog
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// eventViewerExport is the way Event Viewer saves selected events as XML
+const eventViewerExport = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<Events>
+<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Kernel-Power" Guid="{331c3b3a-2005-44c2-ac5e-77220c37d6b4}"/>
+    <EventID>41</EventID>
+    <Version>8</Version>
+    <Level>1</Level>
+    <TimeCreated SystemTime="2024-03-05T21:14:07.4915320Z"/>
+    <EventRecordID>112233</EventRecordID>
+    <Channel>System</Channel>
+    <Computer>DESKTOP-TEST</Computer>
+  </System>
+  <EventData>
+    <Data Name="BugcheckCode">159</Data>
+    <Data Name="BugcheckParameter1">0x3</Data>
+    <Data Name="BugcheckParameter2">0xffffe0012ad7e880</Data>
+    <Data Name="BugcheckParameter3">0xfffff8024b9b1960</Data>
+    <Data Name="BugcheckParameter4">0xffffe0012e2d0010</Data>
+    <Data Name="SleepInProgress">0</Data>
+  </EventData>
+</Event>
+<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Service Control Manager" Guid="{555908d1-a6d7-4695-8e1e-26931d2012f4}" EventSourceName="Service Control Manager"/>
+    <EventID Qualifiers="49152">7000</EventID>
+    <Level>2</Level>
+    <TimeCreated SystemTime="2024-03-05T21:15:00.0000000Z"/>
+    <EventRecordID>112240</EventRecordID>
+    <Channel>System</Channel>
+    <Computer>DESKTOP-TEST</Computer>
+  </System>
+  <EventData>
+    <Data Name="param1">Contoso Updater</Data>
+    <Data Name="param2">%%5</Data>
+  </EventData>
+  <RenderingInfo Culture="en-US">
+    <Message>The Contoso Updater service failed to start due to the following error: 
+Access is denied.</Message>
+  </RenderingInfo>
+</Event>
+</Events>`
+
+// wevtutilExport is the way wevtutil qe /f:xml prints events, without a
+// root element
+const wevtutilExport = `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><Provider Name='Application Error'/><EventID Qualifiers='0'>1000</EventID><Level>2</Level><TimeCreated SystemTime='2024-03-06T08:00:00.1234567Z'/><EventRecordID>5</EventRecordID><Channel>Application</Channel><Computer>PC</Computer></System><EventData><Data>game.exe</Data><Data>1.0.0.0</Data><Data>c0000005</Data></EventData></Event><Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'><System><Provider Name='Microsoft-Windows-WindowsUpdateClient'/><EventID>20</EventID><Level>2</Level><TimeCreated SystemTime='2024-03-06T09:00:00Z'/><EventRecordID>6</EventRecordID><Channel>System</Channel><Computer>PC</Computer></System><UserData><Install xmlns='http://manifests.microsoft.com/win/2004/08/windows/events'><Update><Title>2024-03 Cumulative Update</Title></Update><errorCode>0x80070005</errorCode></Install></UserData></Event>`
+
+func encodeUTF16(text string) []byte {
+	data := []byte{0xFF, 0xFE}
+
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+
+	return data
+}
+
+func TestParse(t *testing.T) {
+	kernelPower := Event{
+		Provider:    "Microsoft-Windows-Kernel-Power",
+		EventID:     41,
+		Level:       1,
+		TimeCreated: time.Date(2024, 3, 5, 21, 14, 7, 491532000, time.UTC),
+		RecordID:    112233,
+		Channel:     "System",
+		Computer:    "DESKTOP-TEST",
+		Data: []Data{
+			{Name: "BugcheckCode", Value: "159"},
+			{Name: "BugcheckParameter1", Value: "0x3"},
+			{Name: "BugcheckParameter2", Value: "0xffffe0012ad7e880"},
+			{Name: "BugcheckParameter3", Value: "0xfffff8024b9b1960"},
+			{Name: "BugcheckParameter4", Value: "0xffffe0012e2d0010"},
+			{Name: "SleepInProgress", Value: "0"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected []Event
+	}{
+		{
+			name: "Event Viewer export",
+			data: []byte(eventViewerExport),
+			expected: []Event{
+				kernelPower,
+				{
+					Provider:    "Service Control Manager",
+					EventID:     7000,
+					Level:       2,
+					TimeCreated: time.Date(2024, 3, 5, 21, 15, 0, 0, time.UTC),
+					RecordID:    112240,
+					Channel:     "System",
+					Computer:    "DESKTOP-TEST",
+					Message:     "The Contoso Updater service failed to start due to the following error: \nAccess is denied.",
+					Data: []Data{
+						{Name: "param1", Value: "Contoso Updater"},
+						{Name: "param2", Value: "%%5"},
+					},
+				},
+			},
+		},
+		{
+			name: "wevtutil output",
+			data: []byte(wevtutilExport),
+			expected: []Event{
+				{
+					Provider:    "Application Error",
+					EventID:     1000,
+					Level:       2,
+					TimeCreated: time.Date(2024, 3, 6, 8, 0, 0, 123456700, time.UTC),
+					RecordID:    5,
+					Channel:     "Application",
+					Computer:    "PC",
+					Data: []Data{
+						{Value: "game.exe"},
+						{Value: "1.0.0.0"},
+						{Value: "c0000005"},
+					},
+				},
+				{
+					Provider:    "Microsoft-Windows-WindowsUpdateClient",
+					EventID:     20,
+					Level:       2,
+					TimeCreated: time.Date(2024, 3, 6, 9, 0, 0, 0, time.UTC),
+					RecordID:    6,
+					Channel:     "System",
+					Computer:    "PC",
+					Data: []Data{
+						{Name: "Title", Value: "2024-03 Cumulative Update"},
+						{Name: "errorCode", Value: "0x80070005"},
+					},
+				},
+			},
+		},
+		{
+			name:     "UTF-16",
+			data:     encodeUTF16(strings.Replace(eventViewerExport, "utf-8", "UTF-16", 1)),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(strings.NewReader(string(tt.data)))
+
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			if tt.expected == nil {
+				// same events as the UTF-8 export
+				tt.expected, _ = Parse(strings.NewReader(eventViewerExport))
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Parse() = %+v, expected %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		events   int
+		expected error
+	}{
+		{name: "empty", data: "", expected: ErrNoEvents},
+		{name: "other XML", data: `<?xml version="1.0"?><Settings><Value>1</Value></Settings>`, expected: ErrNoEvents},
+		{name: "unsupported encoding", data: `<?xml version="1.0" encoding="windows-1252"?><Events></Events>`},
+		{name: "truncated", data: eventViewerExport[:strings.Index(eventViewerExport, "Service Control Manager")], events: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(strings.NewReader(tt.data))
+
+			if err == nil {
+				t.Fatalf("Parse() = %+v, expected an error", result)
+			}
+
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Parse() error = %v, expected %v", err, tt.expected)
+			}
+
+			if len(result) != tt.events {
+				t.Errorf("Parse() returned %d events, expected %d", len(result), tt.events)
+			}
+		})
+	}
+}
+
+func TestEvent_LevelName(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    uint8
+		expected string
+	}{
+		{name: "critical", level: 1, expected: "Critical"},
+		{name: "error", level: 2, expected: "Error"},
+		{name: "classic information", level: 0, expected: "Information"},
+		{name: "custom", level: 16, expected: "Level 16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := (Event{Level: tt.level}).LevelName(); result != tt.expected {
+				t.Errorf("LevelName() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Parse(strings.NewReader(eventViewerExport))
+	}
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func main() {
-	err := commands.LoadRepo()
+	err := commands.LoadRepo(os.Getenv("ERRORBOT_DATA_DIR"))
 
 	if err != nil {
 		log.Fatal(err)
@@ -19,7 +19,8 @@ func main() {
 	client := tempest.NewHTTPClient(tempest.HTTPClientOptions{
 		PublicKey: os.Getenv("DISCORD_PUBLIC_KEY"),
 		BaseClientOptions: tempest.BaseClientOptions{
-			Token: os.Getenv("DISCORD_BOT_TOKEN"),
+			Token:            os.Getenv("DISCORD_BOT_TOKEN"),
+			ComponentHandler: commands.HandleComponent,
 		},
 	})
 
@@ -28,6 +29,13 @@ func main() {
 	client.RegisterCommand(commands.BugCheckCommand)
 	client.RegisterCommand(commands.NTStatusCommand)
 	client.RegisterCommand(commands.HResultCommand)
+	client.RegisterCommand(commands.SearchCommand)
+	client.RegisterCommand(commands.ConvertCommand)
+	client.RegisterCommand(commands.ExplainCommand)
+	client.RegisterCommand(commands.DumpCommand)
+	client.RegisterCommand(commands.EventsCommand)
+	client.RegisterCommand(commands.UpdateLogCommand)
+	client.RegisterCommand(commands.AboutCommand)
 
 	err = client.SyncCommandsWithDiscord(nil, nil, false)
 
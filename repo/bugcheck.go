@@ -2,7 +2,7 @@ package repo
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -18,8 +18,8 @@ type BugCheck struct {
 
 type BugCheckRepo []BugCheck
 
-func LoadBugChecks(name string) (BugCheckRepo, error) {
-	file, err := os.ReadFile(name)
+func LoadBugChecks(fsys fs.FS, name string) (BugCheckRepo, error) {
+	file, err := fs.ReadFile(fsys, name)
 
 	if err != nil {
 		return nil, err
@@ -36,7 +36,33 @@ func LoadBugChecks(name string) (BugCheckRepo, error) {
 }
 
 func (repo Repo) FindBugCheck(code uint32) []ErrorInfo {
-	return repo.BugCheck.FindCode(code)
+	return repo.Find(KindBugCheck, code)
+}
+
+// FindBugCheckCode looks up bug checks with their parameters in the bug check
+// catalogs of the repo.
+func (repo Repo) FindBugCheckCode(code uint32) []BugCheck {
+	matches := []BugCheck{}
+
+	for _, catalog := range repo.bugCheckCatalogs() {
+		matches = append(matches, pick(catalog.bugChecks, catalog.codeIndex().Code(code))...)
+	}
+
+	return matches
+}
+
+// bugCheckCatalogs returns the catalogs that keep the parameters of their bug
+// checks.
+func (repo Repo) bugCheckCatalogs() []*ListCatalog {
+	catalogs := []*ListCatalog{}
+
+	for _, catalog := range repo.Catalogs().OfKind(KindBugCheck) {
+		if list, ok := catalog.(*ListCatalog); ok && list.bugChecks != nil {
+			catalogs = append(catalogs, list)
+		}
+	}
+
+	return catalogs
 }
 
 func (bugChecks BugCheckRepo) FindCode(code uint32) []ErrorInfo {
@@ -213,9 +213,7 @@ func TestBugCheckRepo_FindCode(t *testing.T) {
 }
 
 func TestRepo_FindBugCheck(t *testing.T) {
-	repo := Repo{
-		BugCheck: createTestBugCheckRepo(),
-	}
+	repo := New(newBugCheckCatalog(KindBugCheck.String(), createTestBugCheckRepo()))
 
 	tests := []struct {
 		name     string
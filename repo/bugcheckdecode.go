@@ -0,0 +1,239 @@
+package repo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// BugCheckParameter is one of the parameters a bug check was raised with.
+type BugCheckParameter struct {
+	Value uint64
+	// Description says what the parameter holds
+	Description string
+	// Interpretation explains the value, if it is understood
+	Interpretation string
+}
+
+// BugCheckDecoder interprets the parameters of a bug check. The parameters
+// come with their values and the descriptions from the catalog, decoders
+// replace the descriptions that depend on the values and fill in the
+// interpretations.
+type BugCheckDecoder func(repo Repo, parameters []BugCheckParameter)
+
+var bugCheckDecoders = map[uint32]BugCheckDecoder{
+	0x0000001E: decodeKmodeException,
+	0x00000050: decodePageFault,
+	0x0000007E: decodeExceptionCode,
+	0x0000008E: decodeExceptionCode,
+	0x0000009F: decodeDriverPowerStateFailure,
+	0x00000133: decodeDPCWatchdogViolation,
+	0x000000EF: decodeCriticalProcessDied,
+	0x000000F4: decodeCriticalObjectTermination,
+	0x1000007E: decodeExceptionCode,
+	0x1000008E: decodeExceptionCode,
+	0xC000021A: decodeWinlogonFatalError,
+}
+
+// DecodeBugCheck explains the parameters of a bug check. Bug checks without
+// a decoder get their parameters described by the catalog documentation.
+func (repo Repo) DecodeBugCheck(code uint32, values [4]uint64) []BugCheckParameter {
+	parameters := make([]BugCheckParameter, len(values))
+
+	var documentation []string
+
+	if matches := repo.FindBugCheckCode(code); len(matches) > 0 {
+		documentation = matches[0].Parameters
+	}
+
+	for i, value := range values {
+		parameters[i].Value = value
+
+		if i < len(documentation) {
+			parameters[i].Description = documentation[i]
+		}
+	}
+
+	if decode, ok := bugCheckDecoders[code]; ok {
+		decode(repo, parameters)
+	}
+
+	return parameters
+}
+
+// bugCheckSubtype is a flavour of a bug check selected by its first
+// parameter, which changes what the other parameters hold.
+type bugCheckSubtype struct {
+	description string
+	parameters  [3]string
+}
+
+func decodeSubtype(parameters []BugCheckParameter, subtypes map[uint64]bugCheckSubtype) {
+	parameters[0].Description = "Subtype"
+
+	subtype, ok := subtypes[parameters[0].Value]
+
+	if !ok {
+		parameters[0].Interpretation = "Unknown subtype"
+		return
+	}
+
+	parameters[0].Interpretation = subtype.description
+
+	for i, description := range subtype.parameters {
+		parameters[i+1].Description = description
+	}
+}
+
+var driverPowerStateFailureSubtypes = map[uint64]bugCheckSubtype{
+	0x1: {
+		"The device object that is being freed still has an outstanding power request that it has not completed.",
+		[3]string{"The device object", "Reserved", "Reserved"},
+	},
+	0x2: {
+		"The device object completed the IRP for the system power state request, but it did not call PoStartNextPowerIrp.",
+		[3]string{"The target device object, if available", "The device object", "The driver object, if available"},
+	},
+	0x3: {
+		"A device object has been blocking an IRP for too long a time.",
+		[3]string{"The physical device object (PDO) of the stack", "The nt!TRIAGE_9F_POWER structure", "The blocked IRP"},
+	},
+	0x4: {
+		"The power state transition timed out waiting to synchronize with the PnP subsystem.",
+		[3]string{"The time-out value, in seconds", "The thread currently holding onto the PnP lock", "The nt!TRIAGE_9F_PNP structure"},
+	},
+	0x5: {
+		"The device failed to complete a directed power transition within the required amount of time.",
+		[3]string{"The physical device object (PDO) of the stack", "The POP_FX_DEVICE object", "Reserved"},
+	},
+}
+
+func decodeDriverPowerStateFailure(repo Repo, parameters []BugCheckParameter) {
+	decodeSubtype(parameters, driverPowerStateFailureSubtypes)
+
+	if parameters[0].Value == 0x4 {
+		parameters[1].Interpretation = fmt.Sprintf("%d seconds", parameters[1].Value)
+	}
+}
+
+var dpcWatchdogViolationSubtypes = map[uint64]bugCheckSubtype{
+	0x0: {
+		"A single DPC or ISR exceeded its time allotment. The offending component can usually be identified with a stack trace.",
+		[3]string{"The DPC time count, in ticks", "The DPC time allotment, in ticks", "The nt!DPC_WATCHDOG_GLOBAL_TRIAGE_BLOCK structure"},
+	},
+	0x1: {
+		"The system cumulatively spent an extended period of time at IRQL DISPATCH_LEVEL or above.",
+		[3]string{"The watchdog period, in ticks", "The nt!DPC_WATCHDOG_GLOBAL_TRIAGE_BLOCK structure", "Reserved"},
+	},
+}
+
+func decodeDPCWatchdogViolation(repo Repo, parameters []BugCheckParameter) {
+	decodeSubtype(parameters, dpcWatchdogViolationSubtypes)
+
+	switch parameters[0].Value {
+	case 0x0:
+		parameters[1].Interpretation = fmt.Sprintf("%d ticks", parameters[1].Value)
+		parameters[2].Interpretation = fmt.Sprintf("%d ticks", parameters[2].Value)
+	case 0x1:
+		parameters[1].Interpretation = fmt.Sprintf("%d ticks", parameters[1].Value)
+	}
+}
+
+// decodeExceptionCode interprets the exception code the exception bug
+// checks have in their first parameter.
+func decodeExceptionCode(repo Repo, parameters []BugCheckParameter) {
+	parameters[0].Interpretation = repo.describeStatus(parameters[0].Value)
+}
+
+func decodeKmodeException(repo Repo, parameters []BugCheckParameter) {
+	decodeExceptionCode(repo, parameters)
+
+	// the exception information of access violations holds the type of
+	// access and the address that was accessed
+	if uint32(parameters[0].Value) == 0xC0000005 {
+		parameters[2].Description = "The type of access"
+		parameters[2].Interpretation = map[uint64]string{
+			0: "Read",
+			1: "Write",
+			8: "Execute (DEP violation)",
+		}[parameters[2].Value]
+
+		parameters[3].Description = "The address that was accessed"
+		parameters[3].Interpretation = describeAddress(parameters[3].Value)
+	}
+}
+
+var pageFaultTypes = map[uint64]string{
+	0x0: "NONPAGED_BUGCHECK_FREED_PTE: the address referenced is on a page table entry marked as free.",
+	0x2: "NONPAGED_BUGCHECK_NOT_PRESENT_PAGE_TABLE: the address referenced does not have a valid active page table entry.",
+	0x3: "NONPAGED_BUGCHECK_WRONG_SESSION: a session space address was referenced in the context of a process that has no session.",
+	0x4: "NONPAGED_BUGCHECK_VA_NOT_CANONICAL: the address referenced is not a canonical virtual address.",
+	0xF: "NONPAGED_BUGCHECK_USER_VA_ACCESS_INCONSISTENT: kernel mode code accessed a user mode address when such access is not allowed.",
+}
+
+func decodePageFault(repo Repo, parameters []BugCheckParameter) {
+	parameters[0].Interpretation = describeAddress(parameters[0].Value)
+
+	parameters[1].Description = "The type of access"
+	parameters[1].Interpretation = map[uint64]string{
+		0x0:  "Read",
+		0x1:  "Write (before Windows 10, or on ARM)",
+		0x2:  "Write",
+		0x8:  "Execute (on ARM)",
+		0x10: "Execute",
+	}[parameters[1].Value]
+
+	parameters[3].Description = "The type of page fault"
+	parameters[3].Interpretation = pageFaultTypes[parameters[3].Value]
+}
+
+func decodeCriticalProcessDied(repo Repo, parameters []BugCheckParameter) {
+	parameters[1].Interpretation = map[uint64]string{
+		0: "A process terminated",
+		1: "A thread terminated",
+	}[parameters[1].Value]
+}
+
+func decodeCriticalObjectTermination(repo Repo, parameters []BugCheckParameter) {
+	parameters[0].Description = "The terminating object type"
+	parameters[0].Interpretation = map[uint64]string{
+		0x3: "Process",
+		0x6: "Thread",
+	}[parameters[0].Value]
+}
+
+func decodeWinlogonFatalError(repo Repo, parameters []BugCheckParameter) {
+	parameters[1].Interpretation = repo.describeStatus(parameters[1].Value)
+}
+
+// describeStatus names an NTSTATUS code stored in a parameter. Codes
+// sign-extended to 64 bits are accepted.
+func (repo Repo) describeStatus(value uint64) string {
+	if value > math.MaxUint32 && value>>32 != math.MaxUint32 {
+		return ""
+	}
+
+	matches := repo.FindNTStatus(uint32(value))
+
+	if len(matches) == 0 {
+		return ""
+	}
+
+	description, _, _ := strings.Cut(matches[0].Description, "\n")
+
+	if description == "" {
+		return matches[0].Name
+	}
+
+	return fmt.Sprintf("%s: %s", matches[0].Name, description)
+}
+
+// describeAddress points out addresses in the lowest 64 KB, which are never
+// mapped and usually come from dereferencing a NULL pointer.
+func describeAddress(address uint64) string {
+	if address < 0x10000 {
+		return "Likely a NULL pointer dereference"
+	}
+
+	return ""
+}
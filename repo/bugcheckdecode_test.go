@@ -0,0 +1,177 @@
+package repo
+
+import (
+	"testing"
+)
+
+func createTestDecodeRepo() Repo {
+	repo := New(
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{
+			{Code: 0xC0000005, Name: "STATUS_ACCESS_VIOLATION", Description: "The instruction referenced memory it could not access."},
+			{Code: 0xC0000409, Name: "STATUS_STACK_BUFFER_OVERRUN", Description: "The system detected an overrun of a stack-based buffer."},
+		}, nil),
+		newBugCheckCatalog(KindBugCheck.String(), BugCheckRepo{
+			{Code: 0x1E, Name: "KMODE_EXCEPTION_NOT_HANDLED", Parameters: []string{"The exception code", "The address", "Parameter 0", "Parameter 1"}},
+			{Code: 0x7E, Name: "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED", Parameters: []string{"The exception code", "The address", "The exception record", "The context record"}},
+			{Code: 0x9F, Name: "DRIVER_POWER_STATE_FAILURE"},
+			{Code: 0xD1, Name: "DRIVER_IRQL_NOT_LESS_OR_EQUAL", Parameters: []string{"Memory referenced", "IRQL", "Access type", "Address"}},
+		}),
+	)
+
+	return repo
+}
+
+func TestRepo_DecodeBugCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		values   [4]uint64
+		index    int
+		expected BugCheckParameter
+	}{
+		{
+			name:     "exception code",
+			code:     0x7E,
+			values:   [4]uint64{0xC0000005, 0xFFFFF80012345678},
+			index:    0,
+			expected: BugCheckParameter{Value: 0xC0000005, Description: "The exception code", Interpretation: "STATUS_ACCESS_VIOLATION: The instruction referenced memory it could not access."},
+		},
+		{
+			name:     "sign-extended exception code",
+			code:     0x7E,
+			values:   [4]uint64{0xFFFFFFFFC0000409},
+			index:    0,
+			expected: BugCheckParameter{Value: 0xFFFFFFFFC0000409, Description: "The exception code", Interpretation: "STATUS_STACK_BUFFER_OVERRUN: The system detected an overrun of a stack-based buffer."},
+		},
+		{
+			name:     "unknown exception code",
+			code:     0x7E,
+			values:   [4]uint64{0x80000003},
+			index:    0,
+			expected: BugCheckParameter{Value: 0x80000003, Description: "The exception code"},
+		},
+		{
+			name:     "access violation type",
+			code:     0x1E,
+			values:   [4]uint64{0xC0000005, 0xFFFFF80012345678, 1, 0x28},
+			index:    2,
+			expected: BugCheckParameter{Value: 1, Description: "The type of access", Interpretation: "Write"},
+		},
+		{
+			name:     "access violation address",
+			code:     0x1E,
+			values:   [4]uint64{0xC0000005, 0xFFFFF80012345678, 1, 0x28},
+			index:    3,
+			expected: BugCheckParameter{Value: 0x28, Description: "The address that was accessed", Interpretation: "Likely a NULL pointer dereference"},
+		},
+		{
+			name:     "other exception information",
+			code:     0x1E,
+			values:   [4]uint64{0x80000003, 0xFFFFF80012345678, 1, 0x28},
+			index:    2,
+			expected: BugCheckParameter{Value: 1, Description: "Parameter 0"},
+		},
+		{
+			name:     "power state failure subtype",
+			code:     0x9F,
+			values:   [4]uint64{0x3, 0xFFFF8A0123456789, 0xFFFFF80012345678, 0xFFFF8A0198765432},
+			index:    0,
+			expected: BugCheckParameter{Value: 0x3, Description: "Subtype", Interpretation: "A device object has been blocking an IRP for too long a time."},
+		},
+		{
+			name:     "power state failure subtype parameter",
+			code:     0x9F,
+			values:   [4]uint64{0x3, 0xFFFF8A0123456789, 0xFFFFF80012345678, 0xFFFF8A0198765432},
+			index:    3,
+			expected: BugCheckParameter{Value: 0xFFFF8A0198765432, Description: "The blocked IRP"},
+		},
+		{
+			name:     "power state failure timeout",
+			code:     0x9F,
+			values:   [4]uint64{0x4, 0x12C},
+			index:    1,
+			expected: BugCheckParameter{Value: 0x12C, Description: "The time-out value, in seconds", Interpretation: "300 seconds"},
+		},
+		{
+			name:     "unknown subtype",
+			code:     0x9F,
+			values:   [4]uint64{0x42},
+			index:    0,
+			expected: BugCheckParameter{Value: 0x42, Description: "Subtype", Interpretation: "Unknown subtype"},
+		},
+		{
+			name:     "DPC watchdog single DPC",
+			code:     0x133,
+			values:   [4]uint64{0x0, 0x501, 0x500, 0xFFFFF80012345678},
+			index:    1,
+			expected: BugCheckParameter{Value: 0x501, Description: "The DPC time count, in ticks", Interpretation: "1281 ticks"},
+		},
+		{
+			name:     "DPC watchdog cumulative",
+			code:     0x133,
+			values:   [4]uint64{0x1, 0x1E00, 0xFFFFF80012345678},
+			index:    0,
+			expected: BugCheckParameter{Value: 0x1, Description: "Subtype", Interpretation: "The system cumulatively spent an extended period of time at IRQL DISPATCH_LEVEL or above."},
+		},
+		{
+			name:     "page fault access type",
+			code:     0x50,
+			values:   [4]uint64{0xFFFFF80012345678, 0x10, 0xFFFFF80012345678, 0x2},
+			index:    1,
+			expected: BugCheckParameter{Value: 0x10, Description: "The type of access", Interpretation: "Execute"},
+		},
+		{
+			name:     "page fault type",
+			code:     0x50,
+			values:   [4]uint64{0xFFFFF80012345678, 0x0, 0xFFFFF80012345678, 0x4},
+			index:    3,
+			expected: BugCheckParameter{Value: 0x4, Description: "The type of page fault", Interpretation: "NONPAGED_BUGCHECK_VA_NOT_CANONICAL: the address referenced is not a canonical virtual address."},
+		},
+		{
+			name:     "critical process died",
+			code:     0xEF,
+			values:   [4]uint64{0xFFFF8A0123456789, 0x1},
+			index:    1,
+			expected: BugCheckParameter{Value: 0x1, Interpretation: "A thread terminated"},
+		},
+		{
+			// P3 and P4 are reserved, so values that look like statuses are not guessed at
+			name:     "critical process died reserved",
+			code:     0xEF,
+			values:   [4]uint64{0xFFFF8A0123456789, 0x0, 0xC0000005},
+			index:    2,
+			expected: BugCheckParameter{Value: 0xC0000005},
+		},
+		{
+			name:     "no decoder",
+			code:     0xD1,
+			values:   [4]uint64{0x28, 0x2, 0x0, 0xFFFFF80012345678},
+			index:    1,
+			expected: BugCheckParameter{Value: 0x2, Description: "IRQL"},
+		},
+	}
+
+	repo := createTestDecodeRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parameters := repo.DecodeBugCheck(tt.code, tt.values)
+
+			if len(parameters) != 4 {
+				t.Fatalf("DecodeBugCheck(0x%X) returned %d parameters, expected 4", tt.code, len(parameters))
+			}
+
+			if result := parameters[tt.index]; result != tt.expected {
+				t.Errorf("DecodeBugCheck(0x%X)[%d] = %+v, expected %+v", tt.code, tt.index, result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkRepo_DecodeBugCheck(b *testing.B) {
+	repo := createTestDecodeRepo()
+
+	for i := 0; i < b.N; i++ {
+		repo.DecodeBugCheck(0x1E, [4]uint64{0xC0000005, 0xFFFFF80012345678, 1, 0x28})
+	}
+}
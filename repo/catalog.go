@@ -0,0 +1,169 @@
+package repo
+
+import "sync"
+
+// Catalog is a namespace of error codes, such as the HRESULTs of winerror.h
+// or the Windows Update Agent codes of wuerror.h. Several catalogs may hold
+// codes of the same kind.
+type Catalog interface {
+	// Name is the name of the catalog as shown to users
+	Name() string
+	Kind() Kind
+	FindCode(code uint32) []ErrorInfo
+	// FindName looks up entries by their symbolic name the same way
+	// Repo.FindByName does
+	FindName(name string) []ErrorInfo
+	// Search ranks the entries of the catalog by how well their description
+	// matches the query the same way Repo.Search does
+	Search(query string) []SearchResult
+	// Facilities names the facilities of the codes in the catalog, if the
+	// kind of codes has any
+	Facilities() map[uint16]string
+}
+
+// Registry lists the catalogs of a repo in the order they are reported in.
+type Registry []Catalog
+
+// OfKind returns the catalogs holding codes of the given kind.
+func (registry Registry) OfKind(kind Kind) Registry {
+	catalogs := Registry{}
+
+	for _, catalog := range registry {
+		if catalog.Kind() == kind {
+			catalogs = append(catalogs, catalog)
+		}
+	}
+
+	return catalogs
+}
+
+// ListCatalog is a catalog backed by a list of entries, which is what all
+// the YAML catalogs are. New namespaces only need their entries loaded into
+// one.
+type ListCatalog struct {
+	name       string
+	kind       Kind
+	entries    []ErrorInfo
+	facilities map[uint16]string
+	// texts holds additional text to search for each entry, e.g. the
+	// parameters of bug checks
+	texts [][]string
+	// bugChecks holds the bug checks the entries of bug check catalogs were
+	// made from, for the lookups that return their parameters
+	bugChecks BugCheckRepo
+
+	index *Index
+
+	searchOnce sync.Once
+	search     *searchIndex
+}
+
+// NewListCatalog creates an indexed catalog from the given entries.
+func NewListCatalog(name string, kind Kind, entries []ErrorInfo, facilities map[uint16]string) *ListCatalog {
+	catalog := newListCatalog(name, kind, entries, facilities)
+	catalog.buildIndex()
+
+	return catalog
+}
+
+// newListCatalog creates a catalog that is not indexed yet, which repos
+// without an index look codes up in with linear scans.
+func newListCatalog(name string, kind Kind, entries []ErrorInfo, facilities map[uint16]string) *ListCatalog {
+	return &ListCatalog{
+		name:       name,
+		kind:       kind,
+		entries:    entries,
+		facilities: facilities,
+	}
+}
+
+// NewBugCheckCatalog creates an indexed catalog from the given bug checks.
+func NewBugCheckCatalog(name string, bugChecks BugCheckRepo) *ListCatalog {
+	catalog := newBugCheckCatalog(name, bugChecks)
+	catalog.buildIndex()
+
+	return catalog
+}
+
+func newBugCheckCatalog(name string, bugChecks BugCheckRepo) *ListCatalog {
+	entries := make([]ErrorInfo, len(bugChecks))
+	texts := make([][]string, len(bugChecks))
+
+	for i, bugCheck := range bugChecks {
+		entries[i] = bugCheck.ErrorInfo()
+		texts[i] = bugCheck.Parameters
+	}
+
+	catalog := newListCatalog(name, KindBugCheck, entries, nil)
+	catalog.texts = texts
+	catalog.bugChecks = bugChecks
+
+	return catalog
+}
+
+func (catalog *ListCatalog) buildIndex() {
+	if catalog.index == nil {
+		catalog.index = NewIndex(catalog.entries)
+	}
+}
+
+func (catalog *ListCatalog) Name() string {
+	return catalog.name
+}
+
+func (catalog *ListCatalog) Kind() Kind {
+	return catalog.kind
+}
+
+func (catalog *ListCatalog) Facilities() map[uint16]string {
+	return catalog.facilities
+}
+
+// Entries returns the entries of the catalog. The slice is shared with the
+// catalog and must not be modified.
+func (catalog *ListCatalog) Entries() []ErrorInfo {
+	return catalog.entries
+}
+
+func (catalog *ListCatalog) FindCode(code uint32) []ErrorInfo {
+	if catalog.index == nil {
+		return FindCode(catalog.entries, code)
+	}
+
+	return pick(catalog.entries, catalog.index.Code(code))
+}
+
+func (catalog *ListCatalog) FindName(name string) []ErrorInfo {
+	positions, _ := catalog.codeIndex().findName(name)
+
+	return pick(catalog.entries, positions)
+}
+
+func (catalog *ListCatalog) Search(query string) []SearchResult {
+	catalog.searchOnce.Do(func() {
+		catalog.search = newSearchIndex(Registry{catalog})
+	})
+
+	return catalog.search.find(Registry{catalog}, query, nil)
+}
+
+// searchTexts returns the text to search for the entry at position i.
+func (catalog *ListCatalog) searchTexts(i int) []string {
+	texts := []string{catalog.entries[i].Description}
+
+	if i < len(catalog.texts) {
+		texts = append(texts, catalog.texts[i]...)
+	}
+
+	return texts
+}
+
+// codeIndex returns the index of the catalog, building a temporary one for
+// catalogs that have not been indexed.
+func (catalog *ListCatalog) codeIndex() *Index {
+	if catalog.index == nil {
+		return NewIndex(catalog.entries)
+	}
+
+	return catalog.index
+}
@@ -0,0 +1,198 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// staticCatalog is a catalog that is not backed by a list, like adapters for
+// namespaces with their own lookup logic would be.
+type staticCatalog struct {
+	entry ErrorInfo
+}
+
+func (catalog staticCatalog) Name() string { return "Winsock" }
+func (catalog staticCatalog) Kind() Kind   { return KindWin32Error }
+
+func (catalog staticCatalog) FindCode(code uint32) []ErrorInfo {
+	if code == catalog.entry.Code {
+		return []ErrorInfo{catalog.entry}
+	}
+
+	return []ErrorInfo{}
+}
+
+func (catalog staticCatalog) FindName(name string) []ErrorInfo {
+	if name == catalog.entry.Name {
+		return []ErrorInfo{catalog.entry}
+	}
+
+	return []ErrorInfo{}
+}
+
+func (catalog staticCatalog) Search(query string) []SearchResult {
+	if query == "connection reset" {
+		return []SearchResult{{ErrorInfo: catalog.entry, Kind: catalog.Kind(), Score: 100}}
+	}
+
+	return []SearchResult{}
+}
+
+func (catalog staticCatalog) Facilities() map[uint16]string { return nil }
+
+var wsaConnReset = ErrorInfo{Code: 10054, Name: "WSAECONNRESET", Description: "An existing connection was forcibly closed by the remote host."}
+
+func createTestRegistryRepo() Repo {
+	var repo Repo
+
+	repo.Register(NewListCatalog("Win32 error", KindWin32Error, []ErrorInfo{
+		{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
+	}, nil))
+	repo.Register(staticCatalog{entry: wsaConnReset})
+	repo.Register(NewListCatalog("DirectX", KindHResult, []ErrorInfo{
+		{Code: 0x887A0005, Name: "DXGI_ERROR_DEVICE_REMOVED", Description: "The GPU device instance has been suspended."},
+	}, map[uint16]string{0x87A: "FACILITY_DXGI"}))
+
+	return repo
+}
+
+func TestRegistry_OfKind(t *testing.T) {
+	catalogs := createTestRegistryRepo().Catalogs()
+
+	if len(catalogs) != 3 {
+		t.Fatalf("Catalogs() returned %d catalogs, expected 3", len(catalogs))
+	}
+
+	names := []string{}
+
+	for _, catalog := range catalogs.OfKind(KindWin32Error) {
+		names = append(names, catalog.Name())
+	}
+
+	if expected := []string{"Win32 error", "Winsock"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("OfKind(KindWin32Error) = %v, expected %v", names, expected)
+	}
+
+	if catalogs := catalogs.OfKind(KindBugCheck); len(catalogs) != 0 {
+		t.Errorf("OfKind(KindBugCheck) returned %d catalogs, expected none", len(catalogs))
+	}
+}
+
+func TestListCatalog(t *testing.T) {
+	catalog := NewListCatalog("DirectX", KindHResult, []ErrorInfo{
+		{Code: 0x887A0005, Name: "DXGI_ERROR_DEVICE_REMOVED", Description: "The GPU device instance has been suspended."},
+		{Code: 0x887A0006, Name: "DXGI_ERROR_DEVICE_HUNG", Description: "The GPU will not respond to more commands."},
+	}, map[uint16]string{0x87A: "FACILITY_DXGI"})
+
+	if matches := catalog.FindCode(0x887A0006); len(matches) != 1 || matches[0].Name != "DXGI_ERROR_DEVICE_HUNG" {
+		t.Errorf("FindCode(0x887A0006) = %v, expected DXGI_ERROR_DEVICE_HUNG", matches)
+	}
+
+	if matches := catalog.FindName("dxgi_error_device_removed"); len(matches) != 1 || matches[0].Code != 0x887A0005 {
+		t.Errorf("FindName(dxgi_error_device_removed) = %v, expected DXGI_ERROR_DEVICE_REMOVED", matches)
+	}
+
+	if results := catalog.Search("gpu commands"); len(results) == 0 || results[0].Name != "DXGI_ERROR_DEVICE_HUNG" || results[0].Kind != KindHResult {
+		t.Errorf("Search(gpu commands) = %v, expected DXGI_ERROR_DEVICE_HUNG first", results)
+	}
+
+	if facility := catalog.Facilities()[0x87A]; facility != "FACILITY_DXGI" {
+		t.Errorf("Facilities()[0x87A] = %q, expected FACILITY_DXGI", facility)
+	}
+}
+
+func TestRepo_RegisteredCatalogs(t *testing.T) {
+	repo := createTestRegistryRepo()
+
+	if matches := repo.FindWin32Error(10054); !reflect.DeepEqual(matches, []ErrorInfo{wsaConnReset}) {
+		t.Errorf("FindWin32Error(10054) = %v, expected WSAECONNRESET", matches)
+	}
+
+	if matches := repo.FindHResult(0x887A0005); len(matches) != 1 || matches[0].Name != "DXGI_ERROR_DEVICE_REMOVED" {
+		t.Errorf("FindHResult(0x887A0005) = %v, expected DXGI_ERROR_DEVICE_REMOVED", matches)
+	}
+
+	if matches := repo.FindByName("WSAECONNRESET"); len(matches) != 1 || matches[0].Kind != KindWin32Error {
+		t.Errorf("FindByName(WSAECONNRESET) = %v, expected WSAECONNRESET", matches)
+	}
+
+	if matches := repo.Suggest("DXGI", 5); len(matches) != 1 || matches[0].Name != "DXGI_ERROR_DEVICE_REMOVED" {
+		t.Errorf("Suggest(DXGI) = %v, expected DXGI_ERROR_DEVICE_REMOVED", matches)
+	}
+
+	if results := repo.Search("connection reset"); len(results) == 0 || results[0].Name != "WSAECONNRESET" {
+		t.Errorf("Search(connection reset) = %v, expected WSAECONNRESET first", results)
+	}
+}
+
+func TestRepo_Lookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected map[string][]string
+	}{
+		{name: "list catalog", code: 5, expected: map[string][]string{"Win32 error": {"ERROR_ACCESS_DENIED"}}},
+		{name: "static catalog", code: 10054, expected: map[string][]string{"Winsock": {"WSAECONNRESET"}}},
+		{name: "HRESULT catalog", code: 0x887A0005, expected: map[string][]string{"DirectX": {"DXGI_ERROR_DEVICE_REMOVED"}}},
+		// the mapped code is reported once, under the first HRESULT catalog
+		{name: "mapped code", code: 0x80070005, expected: map[string][]string{"DirectX": {"HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)"}}},
+		{name: "unknown code", code: 0xDEADBEEF, expected: map[string][]string{}},
+	}
+
+	repo := createTestRegistryRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := map[string][]string{}
+
+			for _, match := range repo.Lookup(tt.code) {
+				for _, info := range match.Matches {
+					result[match.Catalog.Name()] = append(result[match.Catalog.Name()], info.Name)
+				}
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Lookup(0x%08X) = %v, expected %v", tt.code, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	repo := createFullTestRepo()
+	names := []string{}
+
+	for _, catalog := range repo.Catalogs() {
+		names = append(names, catalog.Name())
+	}
+
+	expected := []string{"bug check", "HRESULT", "Win32 error", "NTSTATUS"}
+
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Catalogs() = %v, expected %v", names, expected)
+	}
+
+	if catalogs := (Repo{}).Catalogs(); len(catalogs) != 0 {
+		t.Errorf("Catalogs() of an empty repo = %v, expected none", catalogs)
+	}
+}
+
+func TestRepo_Facilities(t *testing.T) {
+	repo := createTestRegistryRepo()
+
+	if name, ok := repo.FacilityName(KindHResult, 0x87A); !ok || name != "FACILITY_DXGI" {
+		t.Errorf("FacilityName(KindHResult, 0x87A) = %q, %v, expected FACILITY_DXGI", name, ok)
+	}
+
+	if name, ok := repo.FacilityName(KindNTStatus, 0x87A); ok {
+		t.Errorf("FacilityName(KindNTStatus, 0x87A) = %q, expected no facility", name)
+	}
+
+	if facility, ok := repo.FindFacility("facility_dxgi"); !ok || facility != 0x87A {
+		t.Errorf("FindFacility(facility_dxgi) = 0x%X, %v, expected 0x87A", facility, ok)
+	}
+
+	if _, ok := repo.FindFacility("FACILITY_NOPE"); ok {
+		t.Error("FindFacility(FACILITY_NOPE) expected no facility")
+	}
+}
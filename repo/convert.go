@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+)
+
+// Conversion is a code as it appears in one of the error namespaces.
+type Conversion struct {
+	Kind Kind
+	// Expression says how the code is derived, e.g. HRESULT_FROM_WIN32(5)
+	Expression string
+	Code       uint32
+	// Matches holds the catalog entries with the code
+	Matches []ErrorInfo
+}
+
+// Convert shows a code in every error namespace it maps to. Mapped codes are
+// unwrapped first, so that 0x80070005, 0xC0070005 and 5 all convert to the
+// same Win32 error, the NTSTATUS codes the mapping table translates to it,
+// its HRESULT_FROM_WIN32 and NTSTATUS_FROM_WIN32 forms, and NTSTATUS codes
+// convert to their HRESULT_FROM_NT form.
+func (repo Repo) Convert(code uint32) []Conversion {
+	conversions := []Conversion{}
+
+	if bugChecks := repo.findCode(KindBugCheck, code); len(bugChecks) > 0 {
+		conversions = append(conversions, Conversion{
+			Kind:       KindBugCheck,
+			Expression: fmt.Sprintf("0x%08X", code),
+			Code:       code,
+			Matches:    bugChecks,
+		})
+	}
+
+	hr := winerror.HResult(code)
+
+	if hr.N() {
+		// HRESULT_FROM_NT, convert the NTSTATUS it wraps
+		code ^= winerror.FACILITY_NT_BIT
+	}
+
+	status := winerror.NTStatus(code)
+
+	switch {
+	case hr.S() && !hr.R() && !hr.N() && hr.Facility() == winerror.FACILITY_WIN32:
+		return append(conversions, repo.convertWin32Error(uint32(hr.Code()))...)
+	case status.Sev() == winerror.STATUS_SEVERITY_ERROR && status.Facility() == winerror.FACILITY_NTWIN32:
+		return append(conversions, repo.convertWin32Error(uint32(status.Code()))...)
+	case hr.N():
+		return append(conversions, repo.convertNTStatus(code)...)
+	case code <= 0xFFFF:
+		return append(conversions, repo.convertWin32Error(code)...)
+	case len(repo.findCode(KindNTStatus, code)) > 0:
+		return append(conversions, repo.convertNTStatus(code)...)
+	}
+
+	return append(conversions, Conversion{
+		Kind:       KindHResult,
+		Expression: fmt.Sprintf("0x%08X", code),
+		Code:       code,
+		Matches:    repo.findCode(KindHResult, code),
+	})
+}
+
+func (repo Repo) convertWin32Error(code uint32) []Conversion {
+	hResult := uint32(winerror.HResultFromWin32(code))
+	ntStatus := uint32(winerror.NTStatusFromWin32(code))
+
+	conversions := []Conversion{
+		{
+			Kind:       KindWin32Error,
+			Expression: fmt.Sprintf("%d", code),
+			Code:       code,
+			Matches:    repo.findCode(KindWin32Error, code),
+		},
+	}
+
+	// the statuses RtlNtStatusToDosError translates to the error are what
+	// actually turns up as it, NTSTATUS_FROM_WIN32 is rarely used
+	for _, status := range repo.MappedNTStatuses(code) {
+		conversions = append(conversions, Conversion{
+			Kind:       KindNTStatus,
+			Expression: fmt.Sprintf("0x%08X, maps to %d", status, code),
+			Code:       status,
+			Matches:    repo.findCode(KindNTStatus, status),
+		})
+	}
+
+	return append(conversions,
+		Conversion{
+			Kind:       KindHResult,
+			Expression: fmt.Sprintf("HRESULT_FROM_WIN32(%d)", code),
+			Code:       hResult,
+			Matches:    repo.findCode(KindHResult, hResult),
+		},
+		Conversion{
+			Kind:       KindNTStatus,
+			Expression: fmt.Sprintf("NTSTATUS_FROM_WIN32(%d)", code),
+			Code:       ntStatus,
+			Matches:    repo.findCode(KindNTStatus, ntStatus),
+		},
+	)
+}
+
+func (repo Repo) convertNTStatus(code uint32) []Conversion {
+	hResult := uint32(winerror.HResultFromNT(code))
+
+	return []Conversion{
+		{
+			Kind:       KindNTStatus,
+			Expression: fmt.Sprintf("0x%08X", code),
+			Code:       code,
+			Matches:    repo.findCode(KindNTStatus, code),
+		},
+		{
+			Kind:       KindHResult,
+			Expression: fmt.Sprintf("HRESULT_FROM_NT(0x%08X)", code),
+			Code:       hResult,
+			Matches:    repo.findCode(KindHResult, hResult),
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createTestConvertRepo() Repo {
+	repo := New(
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{
+			{Code: 0xC0000005, Name: "STATUS_ACCESS_VIOLATION"},
+			{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED"},
+		}, nil),
+		newListCatalog(KindHResult.String(), KindHResult, []ErrorInfo{
+			{Code: 0x80004005, Name: "E_FAIL"},
+			{Code: 0x80070005, Name: "E_ACCESSDENIED"},
+		}, nil),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{
+			{Code: 5, Name: "ERROR_ACCESS_DENIED"},
+			{Code: 126, Name: "ERROR_MOD_NOT_FOUND"},
+		}, nil),
+		newBugCheckCatalog(KindBugCheck.String(), BugCheckRepo{
+			{Code: 0x7E, Name: "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED"},
+		}),
+	)
+	repo.NTStatusMapping = NTStatusMappingRepo{
+		{NTStatus: 0xC0000022, Win32Error: 5},
+		{NTStatus: 0xC0000005, Win32Error: 998},
+		{NTStatus: 0xC00000BA, Win32Error: 5},
+	}
+	repo.BuildIndex()
+
+	return repo
+}
+
+// conversionSummary keeps the parts of the conversions the tests compare
+type conversionSummary struct {
+	Expression string
+	Code       uint32
+	Names      []string
+}
+
+func summarizeConversions(conversions []Conversion) []conversionSummary {
+	summaries := []conversionSummary{}
+
+	for _, conversion := range conversions {
+		names := []string{}
+
+		for _, match := range conversion.Matches {
+			names = append(names, match.Name)
+		}
+
+		summaries = append(summaries, conversionSummary{conversion.Expression, conversion.Code, names})
+	}
+
+	return summaries
+}
+
+func TestRepo_Convert(t *testing.T) {
+	accessDenied := []conversionSummary{
+		{"5", 5, []string{"ERROR_ACCESS_DENIED"}},
+		{"0xC0000022, maps to 5", 0xC0000022, []string{"STATUS_ACCESS_DENIED"}},
+		{"0xC00000BA, maps to 5", 0xC00000BA, []string{}},
+		{"HRESULT_FROM_WIN32(5)", 0x80070005, []string{"E_ACCESSDENIED"}},
+		{"NTSTATUS_FROM_WIN32(5)", 0xC0070005, []string{}},
+	}
+
+	accessViolation := []conversionSummary{
+		{"0xC0000005", 0xC0000005, []string{"STATUS_ACCESS_VIOLATION"}},
+		{"HRESULT_FROM_NT(0xC0000005)", 0xD0000005, []string{}},
+	}
+
+	tests := []struct {
+		name     string
+		code     uint32
+		expected []conversionSummary
+	}{
+		{name: "Win32 error", code: 5, expected: accessDenied},
+		{name: "HRESULT_FROM_WIN32", code: 0x80070005, expected: accessDenied},
+		{name: "NTSTATUS_FROM_WIN32", code: 0xC0070005, expected: accessDenied},
+		{name: "NTSTATUS", code: 0xC0000005, expected: accessViolation},
+		{name: "HRESULT_FROM_NT", code: 0xD0000005, expected: accessViolation},
+		{
+			name:     "HRESULT_FROM_NT of a mapped Win32 error",
+			code:     0xD0070005,
+			expected: accessDenied,
+		},
+		{
+			name: "plain HRESULT",
+			code: 0x80004005,
+			expected: []conversionSummary{
+				{"0x80004005", 0x80004005, []string{"E_FAIL"}},
+			},
+		},
+		{
+			name: "bug check and Win32 error",
+			code: 0x7E,
+			expected: []conversionSummary{
+				{"0x0000007E", 0x7E, []string{"SYSTEM_THREAD_EXCEPTION_NOT_HANDLED"}},
+				{"126", 126, []string{"ERROR_MOD_NOT_FOUND"}},
+				{"HRESULT_FROM_WIN32(126)", 0x8007007E, []string{}},
+				{"NTSTATUS_FROM_WIN32(126)", 0xC007007E, []string{}},
+			},
+		},
+	}
+
+	repo := createTestConvertRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := summarizeConversions(repo.Convert(tt.code))
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Convert(0x%08X) = %+v, expected %+v", tt.code, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepo_Convert_Kinds(t *testing.T) {
+	repo := createTestConvertRepo()
+	kinds := []Kind{}
+
+	for _, conversion := range repo.Convert(5) {
+		kinds = append(kinds, conversion.Kind)
+	}
+
+	expected := []Kind{KindWin32Error, KindNTStatus, KindNTStatus, KindHResult, KindNTStatus}
+
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("Convert(5) kinds = %v, expected %v", kinds, expected)
+	}
+}
@@ -1,9 +1,23 @@
 package repo
 
+import (
+	"fmt"
+	"strings"
+)
+
 type ErrorInfo struct {
 	Code        uint32 `yaml:"code"`
 	Name        string `yaml:"name"`
 	Description string `yaml:"description"`
+	// Introduced is the first SDK build that defines the code, e.g.
+	// 10.0.19041.0. It is empty for codes that predate the oldest SDK the
+	// catalog was generated from.
+	Introduced string `yaml:"introduced,omitempty"`
+	// Removed is the first SDK build that no longer defines the code
+	Removed string `yaml:"removed,omitempty"`
+	// Translations holds the description in other languages, keyed by the
+	// language names of the message file the catalog was compiled from
+	Translations map[string]string `yaml:"translations,omitempty"`
 }
 
 func FindCode(errors []ErrorInfo, code uint32) []ErrorInfo {
@@ -21,3 +35,32 @@ func FindCode(errors []ErrorInfo, code uint32) []ErrorInfo {
 func (errorInfo ErrorInfo) ErrorInfo() ErrorInfo {
 	return errorInfo
 }
+
+// Availability describes the SDK builds that define the code, e.g.
+// "Available since 10.0.19041", or returns an empty string if the code is
+// defined by every SDK the catalog was generated from.
+func (errorInfo ErrorInfo) Availability() string {
+	introduced := shortBuild(errorInfo.Introduced)
+	removed := shortBuild(errorInfo.Removed)
+
+	switch {
+	case introduced != "" && removed != "":
+		return fmt.Sprintf("Available since %s, removed in %s", introduced, removed)
+	case introduced != "":
+		return fmt.Sprintf("Available since %s", introduced)
+	case removed != "":
+		return fmt.Sprintf("Removed in %s", removed)
+	}
+
+	return ""
+}
+
+// shortBuild drops the trailing .0 revision SDK versions carry, so
+// 10.0.19041.0 reads the same as the Windows build it targets.
+func shortBuild(build string) string {
+	if strings.Count(build, ".") == 3 {
+		return strings.TrimSuffix(build, ".0")
+	}
+
+	return build
+}
@@ -244,3 +244,26 @@ func ExampleErrorInfo_ErrorInfo() {
 	info := err.ErrorInfo()
 	println(info.Name) // ERROR_ACCESS_DENIED
 }
+
+func TestErrorInfo_Availability(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     ErrorInfo
+		expected string
+	}{
+		{name: "always defined", info: ErrorInfo{Code: 5}, expected: ""},
+		{name: "introduced", info: ErrorInfo{Introduced: "10.0.19041.0"}, expected: "Available since 10.0.19041"},
+		{name: "removed", info: ErrorInfo{Removed: "10.0.22621.0"}, expected: "Removed in 10.0.22621"},
+		{name: "introduced and removed", info: ErrorInfo{Introduced: "10.0.17763.0", Removed: "10.0.22000.0"}, expected: "Available since 10.0.17763, removed in 10.0.22000"},
+		{name: "build without revision", info: ErrorInfo{Introduced: "10.0.19041"}, expected: "Available since 10.0.19041"},
+		{name: "revision kept", info: ErrorInfo{Introduced: "10.0.22621.755"}, expected: "Available since 10.0.22621.755"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.info.Availability(); result != tt.expected {
+				t.Errorf("Availability() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
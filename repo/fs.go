@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	catalogs "github.com/dhrdlicka/errorbot/yaml"
+)
+
+// overlayFS serves files from upper when they exist there and falls back to
+// lower otherwise.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (overlay overlayFS) Open(name string) (fs.File, error) {
+	file, err := overlay.upper.Open(name)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return overlay.lower.Open(name)
+	}
+
+	return file, err
+}
+
+// overridden reports whether name is served from upper.
+func (overlay overlayFS) overridden(name string) bool {
+	_, err := fs.Stat(overlay.upper, name)
+
+	return err == nil
+}
+
+// DataFS returns the file system the catalogs are loaded from. If dir is not
+// empty, catalog files found in it take precedence over the embedded ones.
+func DataFS(dir string) fs.FS {
+	if dir == "" {
+		return catalogs.FS
+	}
+
+	return overlayFS{
+		upper: os.DirFS(dir),
+		lower: catalogs.FS,
+	}
+}
@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataFS_Embedded(t *testing.T) {
+	fsys := DataFS("")
+
+	for _, name := range []string{"ntstatus.yml", "hresult.yml", "win32error.yml", "bugcheck.yml", "ntstatusmap.yml", "wuerror.yml", "catalogs.yml"} {
+		if _, err := fs.Stat(fsys, name); err != nil {
+			t.Errorf("embedded catalog %s is missing: %v", name, err)
+		}
+	}
+}
+
+func TestDataFS_Override(t *testing.T) {
+	dir := t.TempDir()
+	override := "- code: 5\n  name: ERROR_OVERRIDDEN\n  description: Overridden.\n"
+
+	if err := os.WriteFile(filepath.Join(dir, "win32error.yml"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := DataFS(dir)
+
+	data, err := fs.ReadFile(fsys, "win32error.yml")
+
+	if err != nil {
+		t.Fatalf("failed to read overridden catalog: %v", err)
+	}
+
+	if string(data) != override {
+		t.Errorf("DataFS(%q) did not prefer the override directory", dir)
+	}
+
+	// files missing from the override directory come from the embedded data
+	if _, err := fs.Stat(fsys, "ntstatus.yml"); err != nil {
+		t.Errorf("DataFS(%q) did not fall back to the embedded catalog: %v", dir, err)
+	}
+}
@@ -2,7 +2,7 @@ package repo
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
 
 	"github.com/dhrdlicka/errorbot/winerror"
 	"gopkg.in/yaml.v3"
@@ -13,8 +13,8 @@ type HResultRepo struct {
 	Codes      []ErrorInfo
 }
 
-func LoadHResults(name string) (HResultRepo, error) {
-	file, err := os.ReadFile(name)
+func LoadHResults(fsys fs.FS, name string) (HResultRepo, error) {
+	file, err := fs.ReadFile(fsys, name)
 
 	if err != nil {
 		return HResultRepo{}, err
@@ -35,6 +35,12 @@ func (repo HResultRepo) FindCode(code uint32) []ErrorInfo {
 }
 
 func (repo Repo) FindHResult(code uint32) []ErrorInfo {
+	return repo.Find(KindHResult, code)
+}
+
+// findMappedHResult resolves HRESULTs that wrap an NTSTATUS or Win32 error
+// code. Such codes are not looked up in the HRESULT catalogs.
+func (repo Repo) findMappedHResult(code uint32) ([]ErrorInfo, bool) {
 	hr := winerror.HResult(code)
 
 	if hr.N() {
@@ -46,7 +52,7 @@ func (repo Repo) FindHResult(code uint32) []ErrorInfo {
 			ntStatusMatches[i].Code = code
 		}
 
-		return ntStatusMatches
+		return ntStatusMatches, true
 	} else if hr.S() && !hr.R() && hr.Facility() == winerror.FACILITY_WIN32 {
 		// this is a mapped Win32 error
 		win32ErrorMatches := repo.FindWin32Error(uint32(hr.Code()))
@@ -56,8 +62,8 @@ func (repo Repo) FindHResult(code uint32) []ErrorInfo {
 			win32ErrorMatches[i].Code = code
 		}
 
-		return win32ErrorMatches
+		return win32ErrorMatches, true
 	}
 
-	return repo.HResult.FindCode(code)
+	return nil, false
 }
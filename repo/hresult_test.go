@@ -26,22 +26,21 @@ func createTestHResultRepo() HResultRepo {
 
 // createTestRepo creates a complete test repository for integration testing
 func createTestRepo() Repo {
-	return Repo{
-		HResult: createTestHResultRepo(),
-		Win32Error: Win32ErrorRepo{
+	hResults := createTestHResultRepo()
+
+	return New(
+		newListCatalog(KindHResult.String(), KindHResult, hResults.Codes, hResults.Facilities),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{
 			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
 			{Code: 87, Name: "ERROR_INVALID_PARAMETER", Description: "The parameter is incorrect."},
-		},
-		NTStatus: NTStatusRepo{
-			Facilities: map[uint16]string{
-				0: "FACILITY_NTWIN32",
-			},
-			Codes: []ErrorInfo{
-				{Code: 0xC0000001, Name: "STATUS_UNSUCCESSFUL", Description: "Unsuccessful."},
-				{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "Access denied."},
-			},
-		},
-	}
+		}, nil),
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{
+			{Code: 0xC0000001, Name: "STATUS_UNSUCCESSFUL", Description: "Unsuccessful."},
+			{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "Access denied."},
+		}, map[uint16]string{
+			0: "FACILITY_NTWIN32",
+		}),
+	)
 }
 
 func TestHResultRepo_FindCode(t *testing.T) {
@@ -292,11 +291,11 @@ func TestRepo_FindHResult_EdgeCases(t *testing.T) {
 	}
 
 	// Repository with empty sub-repositories
-	partialRepo := Repo{
-		HResult:    HResultRepo{Codes: []ErrorInfo{}},
-		Win32Error: Win32ErrorRepo{},
-		NTStatus:   NTStatusRepo{Codes: []ErrorInfo{}},
-	}
+	partialRepo := New(
+		newListCatalog(KindHResult.String(), KindHResult, []ErrorInfo{}, nil),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{}, nil),
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{}, nil),
+	)
 
 	result = partialRepo.FindHResult(0x80070005)
 	if len(result) != 0 {
@@ -354,11 +353,9 @@ func ExampleHResultRepo_FindCode() {
 }
 
 func ExampleRepo_FindHResult() {
-	repo := Repo{
-		Win32Error: Win32ErrorRepo{
-			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
-		},
-	}
+	repo := New(NewListCatalog("Win32 error", KindWin32Error, []ErrorInfo{
+		{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
+	}, nil))
 
 	// This will map Win32 error to HRESULT
 	matches := repo.FindHResult(0x80070005)
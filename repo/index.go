@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Index maps codes and symbolic names to positions in a catalog so that
+// lookups do not have to walk the whole catalog.
+type Index struct {
+	codes  map[uint32][]int
+	names  map[string]int
+	folded map[string][]int
+	sorted []string
+	hex    []string
+}
+
+type indexable interface {
+	ErrorInfo() ErrorInfo
+}
+
+func NewIndex[T indexable](items []T) *Index {
+	index := &Index{
+		codes:  make(map[uint32][]int, len(items)),
+		names:  make(map[string]int, len(items)),
+		folded: make(map[string][]int, len(items)),
+	}
+
+	for i, item := range items {
+		info := item.ErrorInfo()
+
+		if _, ok := index.codes[info.Code]; !ok {
+			index.hex = append(index.hex, fmt.Sprintf("%X", info.Code))
+		}
+
+		index.codes[info.Code] = append(index.codes[info.Code], i)
+
+		if _, ok := index.names[info.Name]; !ok {
+			index.names[info.Name] = i
+		}
+
+		folded := strings.ToUpper(info.Name)
+
+		if _, ok := index.folded[folded]; !ok {
+			index.sorted = append(index.sorted, folded)
+		}
+
+		index.folded[folded] = append(index.folded[folded], i)
+	}
+
+	sort.Strings(index.sorted)
+	sort.Strings(index.hex)
+
+	return index
+}
+
+// Code returns the positions of all catalog entries with the given code.
+func (index *Index) Code(code uint32) []int {
+	return index.codes[code]
+}
+
+// Name returns the position of the catalog entry with the given name.
+func (index *Index) Name(name string) (int, bool) {
+	i, ok := index.names[name]
+	return i, ok
+}
+
+// FoldedName returns the positions of all catalog entries whose name matches
+// the given one regardless of case.
+func (index *Index) FoldedName(name string) []int {
+	return index.folded[strings.ToUpper(name)]
+}
+
+// Prefix returns the positions of all catalog entries whose name starts with
+// the given prefix regardless of case, ordered by name.
+func (index *Index) Prefix(prefix string) []int {
+	prefix = strings.ToUpper(prefix)
+	positions := []int{}
+
+	for i := sort.SearchStrings(index.sorted, prefix); i < len(index.sorted); i++ {
+		if !strings.HasPrefix(index.sorted[i], prefix) {
+			break
+		}
+
+		positions = append(positions, index.folded[index.sorted[i]]...)
+	}
+
+	return positions
+}
+
+// CodePrefix returns the positions of all catalog entries whose hexadecimal
+// code starts with the given prefix, ordered by the hexadecimal code. The
+// prefix may carry a 0x prefix and leading zeros.
+func (index *Index) CodePrefix(prefix string) []int {
+	positions := []int{}
+
+	prefix = strings.ToUpper(prefix)
+	prefix = strings.TrimPrefix(prefix, "0X")
+
+	if prefix == "" || strings.Trim(prefix, "0123456789ABCDEF") != "" {
+		return positions
+	}
+
+	if prefix = strings.TrimLeft(prefix, "0"); prefix == "" {
+		prefix = "0"
+	}
+
+	for i := sort.SearchStrings(index.hex, prefix); i < len(index.hex); i++ {
+		if !strings.HasPrefix(index.hex[i], prefix) {
+			break
+		}
+
+		code, _ := strconv.ParseUint(index.hex[i], 16, 32)
+
+		positions = append(positions, index.codes[uint32(code)]...)
+	}
+
+	return positions
+}
+
+// pick returns the items at the given positions. The result is always a new
+// slice so callers are free to modify it.
+func pick[T any](items []T, positions []int) []T {
+	matches := make([]T, 0, len(positions))
+
+	for _, i := range positions {
+		matches = append(matches, items[i])
+	}
+
+	return matches
+}
+
+type repoIndex struct {
+	catalogs Registry
+	search   *searchIndex
+
+	ntStatusMapping *ntStatusMappingIndex
+}
+
+// indexes returns the repo index. Repos that have not been indexed, such as
+// ones declared as struct literals, get a temporary one that leaves their
+// catalogs untouched, so lookups in them stay safe to run concurrently.
+func (repo Repo) indexes() *repoIndex {
+	if repo.index == nil {
+		return newRepoIndex(repo.catalogs, repo.NTStatusMapping)
+	}
+
+	return repo.index
+}
+
+// BuildIndex indexes all catalogs of the repo. Repos returned by New and Load
+// are already indexed; call it again after changing NTStatusMapping.
+func (repo *Repo) BuildIndex() {
+	for _, catalog := range repo.catalogs {
+		if list, ok := catalog.(*ListCatalog); ok {
+			list.buildIndex()
+		}
+	}
+
+	repo.index = newRepoIndex(repo.catalogs, repo.NTStatusMapping)
+}
+
+func newRepoIndex(catalogs Registry, mappings NTStatusMappingRepo) *repoIndex {
+	return &repoIndex{
+		catalogs: catalogs,
+		search:   newSearchIndex(catalogs),
+
+		ntStatusMapping: newNTStatusMappingIndex(mappings),
+	}
+}
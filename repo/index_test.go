@@ -0,0 +1,250 @@
+package repo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewIndex(t *testing.T) {
+	errors := []ErrorInfo{
+		{Code: 1, Name: "ERROR_INVALID_FUNCTION"},
+		{Code: 5, Name: "ERROR_ACCESS_DENIED"},
+		{Code: 5, Name: "ERROR_ACCESS_DENIED_DUPLICATE"},
+	}
+
+	index := NewIndex(errors)
+
+	tests := []struct {
+		name     string
+		code     uint32
+		expected []int
+	}{
+		{name: "single match", code: 1, expected: []int{0}},
+		{name: "duplicate codes keep catalog order", code: 5, expected: []int{1, 2}},
+		{name: "no match", code: 999, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := index.Code(tt.code)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Index.Code(%d) = %v, expected %v", tt.code, result, tt.expected)
+			}
+		})
+	}
+
+	if i, ok := index.Name("ERROR_ACCESS_DENIED"); !ok || i != 1 {
+		t.Errorf("Index.Name(%q) = %d, %v, expected 1, true", "ERROR_ACCESS_DENIED", i, ok)
+	}
+
+	if _, ok := index.Name("ERROR_NONEXISTENT"); ok {
+		t.Errorf("Index.Name(%q) found a match, expected none", "ERROR_NONEXISTENT")
+	}
+}
+
+func TestNewIndex_BugChecks(t *testing.T) {
+	index := NewIndex(createTestBugCheckRepo())
+
+	if result := index.Code(0x50); !reflect.DeepEqual(result, []int{2}) {
+		t.Errorf("Index.Code(0x50) = %v, expected [2]", result)
+	}
+
+	if i, ok := index.Name("DRIVER_IRQL_NOT_LESS_OR_EQUAL"); !ok || i != 3 {
+		t.Errorf("Index.Name(%q) = %d, %v, expected 3, true", "DRIVER_IRQL_NOT_LESS_OR_EQUAL", i, ok)
+	}
+}
+
+// Indexed lookups must return the same results as the linear scans.
+func TestRepo_BuildIndex(t *testing.T) {
+	linear := Repo{catalogs: createFullTestCatalogs()}
+	indexed := createFullTestRepo()
+
+	codes := []uint32{0x00000000, 0x00000005, 0x0000000A, 0x00000050, 0x80070005, 0xC0000001, 0xC0070005, 0xD0000001, 0x90000022, 999}
+
+	for _, code := range codes {
+		t.Run(fmt.Sprintf("0x%08X", code), func(t *testing.T) {
+			if result, expected := indexed.FindNTStatus(code), linear.FindNTStatus(code); !reflect.DeepEqual(result, expected) {
+				t.Errorf("indexed FindNTStatus(0x%08X) = %v, expected %v", code, result, expected)
+			}
+			if result, expected := indexed.FindHResult(code), linear.FindHResult(code); !reflect.DeepEqual(result, expected) {
+				t.Errorf("indexed FindHResult(0x%08X) = %v, expected %v", code, result, expected)
+			}
+			if result, expected := indexed.FindWin32Error(code), linear.FindWin32Error(code); !reflect.DeepEqual(result, expected) {
+				t.Errorf("indexed FindWin32Error(0x%08X) = %v, expected %v", code, result, expected)
+			}
+			if result, expected := indexed.FindBugCheckCode(code), linear.FindBugCheckCode(code); !reflect.DeepEqual(result, expected) {
+				t.Errorf("indexed FindBugCheckCode(0x%08X) = %v, expected %v", code, result, expected)
+			}
+		})
+	}
+}
+
+func TestRepo_BuildIndex_ResultsAreCopies(t *testing.T) {
+	repo := createFullTestRepo()
+
+	// FindHResult renames the Win32 matches it gets back
+	_ = repo.FindHResult(0x80070005)
+
+	if name := catalogEntries(repo, KindWin32Error)[1].Name; name != "ERROR_ACCESS_DENIED" {
+		t.Errorf("indexed lookup modified the catalog, got name %q", name)
+	}
+}
+
+// createLargeWin32ErrorRepo creates a catalog with size sequential codes
+func createLargeWin32ErrorRepo(size int) Repo {
+	errors := make(Win32ErrorRepo, size)
+	for i := range errors {
+		errors[i] = ErrorInfo{Code: uint32(i), Name: fmt.Sprintf("ERROR_%d", i), Description: "Desc"}
+	}
+
+	return New(newListCatalog(KindWin32Error.String(), KindWin32Error, errors, nil))
+}
+
+// Benchmark tests - indexed lookups should take the same time regardless of
+// the catalog size, linear scans grow with it
+func BenchmarkRepo_FindWin32Error_Indexed(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		repo := createLargeWin32ErrorRepo(size)
+		code := uint32(size - 1)
+
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = repo.FindWin32Error(code)
+			}
+		})
+	}
+}
+
+func BenchmarkRepo_FindWin32Error_Linear(b *testing.B) {
+	for _, size := range []int{100, 1000, 10000} {
+		entries := catalogEntries(createLargeWin32ErrorRepo(size), KindWin32Error)
+		code := uint32(size - 1)
+
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = FindCode(entries, code)
+			}
+		})
+	}
+}
+
+func BenchmarkRepo_Loaded_AllCatalogs(b *testing.B) {
+	repo, err := Load("")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	codes := []uint32{0x00000005, 0x0000007E, 0x80070005, 0x8024402C, 0xC0000005, 0xC0070005}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		code := codes[i%len(codes)]
+		_ = repo.FindNTStatus(code)
+		_ = repo.FindHResult(code)
+		_ = repo.FindWin32Error(code)
+		_ = repo.FindBugCheck(code)
+	}
+}
+
+func BenchmarkNewIndex_HResult(b *testing.B) {
+	repo, err := Load("")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = NewIndex(catalogEntries(repo, KindHResult))
+	}
+}
+
+func TestIndex_FoldedNameAndPrefix(t *testing.T) {
+	index := NewIndex([]ErrorInfo{
+		{Code: 0x80004005, Name: "E_FAIL"},
+		{Code: 0x80070005, Name: "E_ACCESSDENIED"},
+		{Code: 0x80004001, Name: "E_NOTIMPL"},
+		{Code: 0x00000000, Name: "S_OK"},
+	})
+
+	if result := index.FoldedName("e_fail"); !reflect.DeepEqual(result, []int{0}) {
+		t.Errorf("Index.FoldedName(%q) = %v, expected [0]", "e_fail", result)
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []int
+	}{
+		{prefix: "e_", expected: []int{1, 0, 2}},
+		{prefix: "E_NOT", expected: []int{2}},
+		{prefix: "S_OK", expected: []int{3}},
+		{prefix: "X", expected: []int{}},
+		{prefix: "", expected: []int{1, 0, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			result := index.Prefix(tt.prefix)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Index.Prefix(%q) = %v, expected %v", tt.prefix, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIndex_CodePrefix(t *testing.T) {
+	index := NewIndex([]ErrorInfo{
+		{Code: 0xC0000005, Name: "STATUS_ACCESS_VIOLATION"},
+		{Code: 0x0000007B, Name: "INACCESSIBLE_BOOT_DEVICE"},
+		{Code: 0x7B000000, Name: "TEST_HIGH_CODE"},
+		{Code: 0x00000000, Name: "STATUS_SUCCESS"},
+	})
+
+	tests := []struct {
+		prefix   string
+		expected []int
+	}{
+		{prefix: "0xC0000005", expected: []int{0}},
+		{prefix: "c0", expected: []int{0}},
+		{prefix: "0x7B", expected: []int{1, 2}},
+		{prefix: "0x0000007B", expected: []int{1, 2}},
+		{prefix: "0x0", expected: []int{3}},
+		{prefix: "0x", expected: []int{}},
+		{prefix: "STATUS", expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			result := index.CodePrefix(tt.prefix)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Index.CodePrefix(%q) = %v, expected %v", tt.prefix, result, tt.expected)
+			}
+		})
+	}
+}
+
+// Repos built with New and Register keep their index, lookups must not
+// build one on every call.
+func TestRepo_IndexedOnce(t *testing.T) {
+	repo := createFullTestRepo()
+
+	if repo.index == nil {
+		t.Fatal("New() returned a repo without an index")
+	}
+
+	index := repo.index
+
+	if repo.FindByName("E_NOTIMPL"); repo.index != index || repo.indexes() != index {
+		t.Error("FindByName() replaced the index of an indexed repo")
+	}
+
+	repo.Register(NewListCatalog("extra", KindWin32Error, []ErrorInfo{{Code: 1, Name: "ERROR_INVALID_FUNCTION"}}, nil))
+
+	if repo.index == nil || repo.index == index {
+		t.Error("Register() did not index the repo again")
+	}
+}
@@ -0,0 +1,232 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestEntry describes a catalog file the repo loads.
+type ManifestEntry struct {
+	// Name is the name of the catalog as shown to users
+	Name string `yaml:"name"`
+	// Type says how the file is read, see catalogTypes
+	Type string `yaml:"type"`
+	// Kind is the kind of codes in catalogs of the list and mc types, e.g.
+	// hresult
+	Kind string `yaml:"kind"`
+	File string `yaml:"file"`
+	// Source is the header, message table or document the file was
+	// generated from
+	Source string `yaml:"source"`
+	// SDK is the build of the Windows SDK the source comes from, e.g.
+	// 10.0.26100.0
+	SDK string `yaml:"sdk"`
+	// Generated is the date the file was generated on
+	Generated string `yaml:"generated"`
+	// SHA256 is the checksum of the embedded file. Files without one are not
+	// verified.
+	SHA256 string `yaml:"sha256"`
+
+	// Entries is the number of entries loaded from the file
+	Entries int `yaml:"-"`
+	// Overridden is set for files loaded from the data directory instead of
+	// the embedded ones. They are not verified, the checksum is for the
+	// embedded file.
+	Overridden bool `yaml:"-"`
+}
+
+// ErrChecksumMismatch is returned for catalog files that do not match the
+// checksum the manifest has for them.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrDuplicateMapping is returned for manifests with more than one
+// ntstatusmap entry.
+var ErrDuplicateMapping = errors.New("the manifest has more than one NTSTATUS mapping")
+
+// Manifest lists the catalog files of the repo in the order lookups report
+// them in.
+type Manifest []ManifestEntry
+
+func LoadManifest(fsys fs.FS, name string) (Manifest, error) {
+	file, err := fs.ReadFile(fsys, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	err = yaml.Unmarshal(file, &manifest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// catalogLoader loads the file of a manifest entry into the repo, registers
+// the catalog it holds and returns the number of entries loaded.
+type catalogLoader func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error)
+
+// catalogTypes maps the types of manifest entries to their loaders. The list
+// type takes plain lists of entries of any kind, which is all a new
+// namespace needs. The mc type takes catalogs compiled from message files,
+// which also name their facilities.
+var catalogTypes = map[string]catalogLoader{
+	"ntstatus": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		statuses, err := LoadNTStatuses(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		repo.register(newListCatalog(entry.Name, KindNTStatus, statuses.Codes, statuses.Facilities))
+
+		return len(statuses.Codes), nil
+	},
+	"hresult": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		hResults, err := LoadHResults(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		repo.register(newListCatalog(entry.Name, KindHResult, hResults.Codes, hResults.Facilities))
+
+		return len(hResults.Codes), nil
+	},
+	"win32error": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		win32Errors, err := LoadWin32Errors(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		repo.register(newListCatalog(entry.Name, KindWin32Error, win32Errors, nil))
+
+		return len(win32Errors), nil
+	},
+	"bugcheck": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		bugChecks, err := LoadBugChecks(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		repo.register(newBugCheckCatalog(entry.Name, bugChecks))
+
+		return len(bugChecks), nil
+	},
+	"wuerror": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		wuErrors, err := LoadWindowsUpdateErrors(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		repo.register(newListCatalog(entry.Name, KindHResult, wuErrors, nil))
+
+		return len(wuErrors), nil
+	},
+	"ntstatusmap": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (count int, err error) {
+		// the mapping is not a catalog, a second one would replace the first
+		if repo.NTStatusMapping != nil {
+			return 0, ErrDuplicateMapping
+		}
+
+		repo.NTStatusMapping, err = LoadNTStatusMappings(fsys, entry.File)
+		return len(repo.NTStatusMapping), err
+	},
+	"mc": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		kind, ok := ParseKind(entry.Kind)
+
+		if !ok {
+			return 0, fmt.Errorf("unknown kind %q", entry.Kind)
+		}
+
+		catalog, err := LoadMessageCatalog(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		repo.register(newListCatalog(entry.Name, kind, catalog.Codes, catalog.Facilities))
+
+		return len(catalog.Codes), nil
+	},
+	"list": func(repo *Repo, fsys fs.FS, entry ManifestEntry) (int, error) {
+		kind, ok := ParseKind(entry.Kind)
+
+		if !ok {
+			return 0, fmt.Errorf("unknown kind %q", entry.Kind)
+		}
+
+		file, err := fs.ReadFile(fsys, entry.File)
+
+		if err != nil {
+			return 0, err
+		}
+
+		var entries []ErrorInfo
+
+		if err := yaml.Unmarshal(file, &entries); err != nil {
+			return 0, err
+		}
+
+		repo.register(newListCatalog(entry.Name, kind, entries, nil))
+
+		return len(entries), nil
+	},
+}
+
+// load verifies the file of the entry and loads it into the repo.
+func (entry *ManifestEntry) load(repo *Repo, fsys fs.FS) error {
+	loader, ok := catalogTypes[entry.Type]
+
+	if !ok {
+		return fmt.Errorf("%s: unknown catalog type %q", entry.File, entry.Type)
+	}
+
+	if overlay, ok := fsys.(overlayFS); ok && overlay.overridden(entry.File) {
+		entry.Overridden = true
+	} else if err := entry.verify(fsys); err != nil {
+		return fmt.Errorf("%s: %w", entry.File, err)
+	}
+
+	count, err := loader(repo, fsys, *entry)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", entry.File, err)
+	}
+
+	entry.Entries = count
+
+	return nil
+}
+
+// verify checks the file of the entry against its checksum.
+func (entry ManifestEntry) verify(fsys fs.FS) error {
+	if entry.SHA256 == "" {
+		return nil
+	}
+
+	file, err := fs.ReadFile(fsys, entry.File)
+
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(file)
+
+	if checksum := hex.EncodeToString(sum[:]); !strings.EqualFold(checksum, entry.SHA256) {
+		return fmt.Errorf("%w: the manifest has %s, the file %s", ErrChecksumMismatch, entry.SHA256, checksum)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package repo
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"catalogs.yml": {Data: []byte("- name: Winsock\n  type: list\n  kind: win32error\n  file: winsock.yml\n")},
+	}
+
+	manifest, err := LoadManifest(fsys, "catalogs.yml")
+
+	if err != nil {
+		t.Fatalf("LoadManifest() unexpected error: %v", err)
+	}
+
+	expected := Manifest{{Name: "Winsock", Type: "list", Kind: "win32error", File: "winsock.yml"}}
+
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("LoadManifest() = %+v, expected %+v", manifest, expected)
+	}
+
+	if _, err := LoadManifest(fsys, "missing.yml"); err == nil {
+		t.Error("LoadManifest() of a missing file expected error")
+	}
+}
+
+func TestLoadFS_Manifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"catalogs.yml": {Data: []byte(`- name: Win32 error
+  type: win32error
+  file: win32error.yml
+- name: Winsock
+  type: list
+  kind: win32error
+  file: winsock.yml
+`)},
+		"win32error.yml": {Data: []byte("- code: 5\n  name: ERROR_ACCESS_DENIED\n  description: Access is denied.\n")},
+		"winsock.yml":    {Data: []byte("- code: 10054\n  name: WSAECONNRESET\n  description: An existing connection was forcibly closed by the remote host.\n")},
+	}
+
+	repo, err := LoadFS(fsys)
+
+	if err != nil {
+		t.Fatalf("LoadFS() unexpected error: %v", err)
+	}
+
+	names := []string{}
+
+	for _, catalog := range repo.Catalogs() {
+		names = append(names, catalog.Name())
+	}
+
+	if expected := []string{"Win32 error", "Winsock"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("LoadFS() catalogs = %v, expected %v", names, expected)
+	}
+
+	if entries := catalogEntries(repo, KindWin32Error); len(entries) != 1 {
+		t.Errorf("LoadFS() loaded %d Win32 errors, expected 1", len(entries))
+	}
+
+	if matches := repo.FindWin32Error(10054); len(matches) != 1 || matches[0].Name != "WSAECONNRESET" {
+		t.Errorf("FindWin32Error(10054) = %v, expected WSAECONNRESET", matches)
+	}
+}
+
+func TestLoadFS_ManifestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{name: "unknown type", manifest: "- name: X\n  type: nope\n  file: x.yml\n", expected: `unknown catalog type "nope"`},
+		{name: "unknown kind", manifest: "- name: X\n  type: list\n  kind: nope\n  file: x.yml\n", expected: `unknown kind "nope"`},
+		{name: "missing file", manifest: "- name: X\n  type: win32error\n  file: missing.yml\n", expected: "missing.yml"},
+		{name: "second mapping", manifest: "- name: X\n  type: ntstatusmap\n  file: map.yml\n- name: Y\n  type: ntstatusmap\n  file: map.yml\n", expected: ErrDuplicateMapping.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"catalogs.yml": {Data: []byte(tt.manifest)},
+				"x.yml":        {Data: []byte("[]\n")},
+				"map.yml":      {Data: []byte("- ntstatus: 0xC0000022\n  win32error: 5\n")},
+			}
+
+			_, err := LoadFS(fsys)
+
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("LoadFS() error = %v, expected it to mention %q", err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := LoadFS(fstest.MapFS{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFS() without a manifest error = %v, expected fs.ErrNotExist", err)
+	}
+}
+
+// Entries of the same type each get a catalog instead of replacing the
+// previous one.
+func TestLoadFS_SameType(t *testing.T) {
+	fsys := fstest.MapFS{
+		"catalogs.yml": {Data: []byte("- name: HRESULT\n  type: hresult\n  file: hresult.yml\n- name: DirectX\n  type: hresult\n  file: directx.yml\n")},
+		"hresult.yml":  {Data: []byte("codes:\n- code: 0x80004005\n  name: E_FAIL\n  description: Unspecified error\n")},
+		"directx.yml":  {Data: []byte("facilities:\n  0x87A: FACILITY_DXGI\ncodes:\n- code: 0x887A0005\n  name: DXGI_ERROR_DEVICE_REMOVED\n  description: The GPU device instance has been suspended.\n")},
+	}
+
+	repo, err := LoadFS(fsys)
+
+	if err != nil {
+		t.Fatalf("LoadFS() unexpected error: %v", err)
+	}
+
+	for _, code := range []uint32{0x80004005, 0x887A0005} {
+		if matches := repo.FindHResult(code); len(matches) != 1 {
+			t.Errorf("FindHResult(0x%08X) = %v, expected one match", code, matches)
+		}
+	}
+
+	if name, ok := repo.FacilityName(KindHResult, 0x87A); !ok || name != "FACILITY_DXGI" {
+		t.Errorf("FacilityName(KindHResult, 0x87A) = %q, %v, expected FACILITY_DXGI", name, ok)
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for name, expected := range map[string]Kind{"bugcheck": KindBugCheck, "hresult": KindHResult, "win32error": KindWin32Error, "ntstatus": KindNTStatus} {
+		if kind, ok := ParseKind(name); !ok || kind != expected {
+			t.Errorf("ParseKind(%q) = %v, %v, expected %v", name, kind, ok, expected)
+		}
+	}
+
+	if _, ok := ParseKind("HRESULT"); ok {
+		t.Error("ParseKind(HRESULT) expected no kind")
+	}
+}
+
+func TestLoadFS_Checksums(t *testing.T) {
+	win32Errors := "- code: 5\n  name: ERROR_ACCESS_DENIED\n  description: Access is denied.\n"
+
+	tests := []struct {
+		name     string
+		sha256   string
+		expected error
+	}{
+		{name: "matching checksum", sha256: "sha256: 3ff07e50851dc023c47a2c4a90152acd084ddd5298a41da7b6d7ffbc3def2101", expected: nil},
+		{name: "uppercase checksum", sha256: "sha256: 3FF07E50851DC023C47A2C4A90152ACD084DDD5298A41DA7B6D7FFBC3DEF2101", expected: nil},
+		{name: "no checksum", sha256: "", expected: nil},
+		{name: "mismatched checksum", sha256: "sha256: 0000000000000000000000000000000000000000000000000000000000000000", expected: ErrChecksumMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"catalogs.yml":   {Data: []byte("- name: Win32 error\n  type: win32error\n  file: win32error.yml\n  " + tt.sha256 + "\n")},
+				"win32error.yml": {Data: []byte(win32Errors)},
+			}
+
+			_, err := LoadFS(fsys)
+
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("LoadFS() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFS_ManifestMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"catalogs.yml": {Data: []byte(`- name: HRESULT
+  type: hresult
+  file: hresult.yml
+  source: winerror.h
+  sdk: 10.0.26100.0
+  generated: 2026-10-17
+`)},
+		"hresult.yml": {Data: []byte("codes:\n- code: 0x80004005\n  name: E_FAIL\n  description: Unspecified error\n- code: 0x80070005\n  name: E_ACCESSDENIED\n  description: General access denied error\n")},
+	}
+
+	repo, err := LoadFS(fsys)
+
+	if err != nil {
+		t.Fatalf("LoadFS() unexpected error: %v", err)
+	}
+
+	expected := Manifest{{Name: "HRESULT", Type: "hresult", File: "hresult.yml", Source: "winerror.h", SDK: "10.0.26100.0", Generated: "2026-10-17", Entries: 2}}
+
+	if !reflect.DeepEqual(repo.Manifest, expected) {
+		t.Errorf("LoadFS() manifest = %+v, expected %+v", repo.Manifest, expected)
+	}
+}
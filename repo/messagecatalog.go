@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"io/fs"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MessageCatalog is a catalog compiled from the message compiler (.mc) file
+// of a component by yamlgen -m mc.
+type MessageCatalog struct {
+	Facilities map[uint16]string `yaml:"facilities"`
+	// Languages lists the languages of the messages. Descriptions are in the
+	// first language a message has text for, translations hold the others.
+	Languages []string    `yaml:"languages"`
+	Codes     []ErrorInfo `yaml:"codes"`
+}
+
+func LoadMessageCatalog(fsys fs.FS, name string) (MessageCatalog, error) {
+	file, err := fs.ReadFile(fsys, name)
+
+	if err != nil {
+		return MessageCatalog{}, err
+	}
+
+	var catalog MessageCatalog
+	err = yaml.Unmarshal(file, &catalog)
+
+	if err != nil {
+		return MessageCatalog{}, err
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+const testMessageCatalog = `facilities:
+  257: FACILITY_SAMPLE_DRIVER
+languages:
+- English
+- German
+codes:
+- code: 0xC1010001
+  name: SAMPLE_E_DEVICE_GONE
+  description: The device was removed.
+  translations:
+    German: Das Gerät wurde entfernt.
+`
+
+func TestLoadMessageCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sample.yml": {Data: []byte(testMessageCatalog)},
+	}
+
+	catalog, err := LoadMessageCatalog(fsys, "sample.yml")
+
+	if err != nil {
+		t.Fatalf("LoadMessageCatalog() unexpected error: %v", err)
+	}
+
+	expected := MessageCatalog{
+		Facilities: map[uint16]string{257: "FACILITY_SAMPLE_DRIVER"},
+		Languages:  []string{"English", "German"},
+		Codes: []ErrorInfo{{
+			Code:         0xC1010001,
+			Name:         "SAMPLE_E_DEVICE_GONE",
+			Description:  "The device was removed.",
+			Translations: map[string]string{"German": "Das Gerät wurde entfernt."},
+		}},
+	}
+
+	if !reflect.DeepEqual(catalog, expected) {
+		t.Errorf("LoadMessageCatalog() = %+v, expected %+v", catalog, expected)
+	}
+
+	if _, err := LoadMessageCatalog(fsys, "missing.yml"); err == nil {
+		t.Error("LoadMessageCatalog() of a missing file expected error")
+	}
+}
+
+func TestLoadFS_MessageCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"catalogs.yml": {Data: []byte("- name: Sample driver\n  type: mc\n  kind: ntstatus\n  file: sample.yml\n")},
+		"sample.yml":   {Data: []byte(testMessageCatalog)},
+	}
+
+	repo, err := LoadFS(fsys)
+
+	if err != nil {
+		t.Fatalf("LoadFS() unexpected error: %v", err)
+	}
+
+	if matches := repo.FindNTStatus(0xC1010001); len(matches) != 1 || matches[0].Translations["German"] != "Das Gerät wurde entfernt." {
+		t.Errorf("FindNTStatus(0xC1010001) = %+v, expected SAMPLE_E_DEVICE_GONE with its translation", matches)
+	}
+
+	if name, ok := repo.FacilityName(KindNTStatus, 257); !ok || name != "FACILITY_SAMPLE_DRIVER" {
+		t.Errorf("FacilityName(KindNTStatus, 257) = %q, %v, expected FACILITY_SAMPLE_DRIVER", name, ok)
+	}
+
+	if repo.Manifest[0].Entries != 1 {
+		t.Errorf("LoadFS() counted %d entries, expected 1", repo.Manifest[0].Entries)
+	}
+
+	if _, err := LoadFS(fstest.MapFS{
+		"catalogs.yml": {Data: []byte("- name: Sample driver\n  type: mc\n  file: sample.yml\n")},
+		"sample.yml":   {Data: []byte(testMessageCatalog)},
+	}); err == nil {
+		t.Error("LoadFS() of a message catalog without a kind expected error")
+	}
+}
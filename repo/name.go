@@ -0,0 +1,154 @@
+package repo
+
+import (
+	"strings"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+)
+
+// Kind identifies the catalog an entry comes from. It lives in winerror so
+// that packages like util can use it without depending on the catalogs.
+type Kind = winerror.Kind
+
+const (
+	KindBugCheck   = winerror.KindBugCheck
+	KindHResult    = winerror.KindHResult
+	KindWin32Error = winerror.KindWin32Error
+	KindNTStatus   = winerror.KindNTStatus
+)
+
+// Kinds lists all catalog kinds in the order results are presented in.
+var Kinds = winerror.Kinds
+
+// kindNames are the names catalog manifests refer to kinds by.
+var kindNames = map[string]Kind{
+	"bugcheck":   KindBugCheck,
+	"hresult":    KindHResult,
+	"win32error": KindWin32Error,
+	"ntstatus":   KindNTStatus,
+}
+
+// ParseKind returns the kind with the given manifest name, e.g. "hresult".
+func ParseKind(name string) (Kind, bool) {
+	kind, ok := kindNames[name]
+	return kind, ok
+}
+
+// NameMatch is a catalog entry found by its symbolic name.
+type NameMatch struct {
+	ErrorInfo
+	Kind Kind
+}
+
+type nameMatchQuality int
+
+const (
+	exactNameMatch nameMatchQuality = iota
+	foldedNameMatch
+	prefixNameMatch
+	noNameMatch
+)
+
+func (index *Index) findName(name string) ([]int, nameMatchQuality) {
+	if i, ok := index.Name(name); ok {
+		return []int{i}, exactNameMatch
+	}
+
+	if positions := index.FoldedName(name); len(positions) > 0 {
+		return positions, foldedNameMatch
+	}
+
+	if positions := index.Prefix(name); len(positions) > 0 {
+		return positions, prefixNameMatch
+	}
+
+	return nil, noNameMatch
+}
+
+// findCatalogName looks up entries by name in a single catalog. Catalogs not
+// backed by lists only report exact matches.
+func findCatalogName(catalog Catalog, name string) ([]ErrorInfo, nameMatchQuality) {
+	list, ok := catalog.(*ListCatalog)
+
+	if !ok {
+		if matches := catalog.FindName(name); len(matches) > 0 {
+			return matches, exactNameMatch
+		}
+
+		return nil, noNameMatch
+	}
+
+	positions, quality := list.codeIndex().findName(name)
+
+	return pick(list.entries, positions), quality
+}
+
+// FindByName looks up entries by their symbolic name in the catalogs of the
+// given kinds, or in all catalogs if no kinds are given. Exact matches are
+// preferred over case-insensitive ones, which are preferred over prefix
+// matches; only the best kind of match found in any catalog is returned.
+func (repo Repo) FindByName(name string, kinds ...Kind) []NameMatch {
+	matches := []NameMatch{}
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return matches
+	}
+
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+
+	catalogs := repo.indexes().catalogs
+	best := noNameMatch
+
+	for _, kind := range kinds {
+		for _, catalog := range catalogs.OfKind(kind) {
+			found, quality := findCatalogName(catalog, name)
+
+			if quality > best {
+				continue
+			} else if quality < best {
+				best = quality
+				matches = matches[:0]
+			}
+
+			for _, info := range found {
+				matches = append(matches, NameMatch{
+					ErrorInfo: info,
+					Kind:      kind,
+				})
+			}
+		}
+	}
+
+	return matches
+}
+
+// FindBugCheckName looks up bug checks by their symbolic name the same way
+// FindByName does.
+func (repo Repo) FindBugCheckName(name string) []BugCheck {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return []BugCheck{}
+	}
+
+	matches := []BugCheck{}
+	best := noNameMatch
+
+	for _, catalog := range repo.bugCheckCatalogs() {
+		positions, quality := catalog.codeIndex().findName(name)
+
+		if quality > best {
+			continue
+		} else if quality < best {
+			best = quality
+			matches = matches[:0]
+		}
+
+		matches = append(matches, pick(catalog.bugChecks, positions)...)
+	}
+
+	return matches
+}
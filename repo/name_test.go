@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepo_FindByName(t *testing.T) {
+	repo := createFullTestRepo()
+
+	tests := []struct {
+		name     string
+		search   string
+		kinds    []Kind
+		expected []NameMatch
+	}{
+		{
+			name:   "exact HRESULT name",
+			search: "E_ACCESSDENIED",
+			expected: []NameMatch{
+				{ErrorInfo: ErrorInfo{Code: 0x80070005, Name: "E_ACCESSDENIED", Description: "Access denied."}, Kind: KindHResult},
+			},
+		},
+		{
+			name:   "case-insensitive NTSTATUS name",
+			search: "status_access_denied",
+			expected: []NameMatch{
+				{ErrorInfo: ErrorInfo{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "Access denied."}, Kind: KindNTStatus},
+			},
+		},
+		{
+			name:   "exact match wins over prefix matches in other catalogs",
+			search: "ERROR_ACCESS_DENIED",
+			expected: []NameMatch{
+				{ErrorInfo: ErrorInfo{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."}, Kind: KindWin32Error},
+			},
+		},
+		{
+			name:   "prefix matches across catalogs",
+			search: "STATUS_",
+			expected: []NameMatch{
+				{ErrorInfo: ErrorInfo{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "Access denied."}, Kind: KindNTStatus},
+				{ErrorInfo: ErrorInfo{Code: 0x00000000, Name: "STATUS_SUCCESS", Description: "Success."}, Kind: KindNTStatus},
+				{ErrorInfo: ErrorInfo{Code: 0xC0000001, Name: "STATUS_UNSUCCESSFUL", Description: "Unsuccessful."}, Kind: KindNTStatus},
+			},
+		},
+		{
+			name:   "bug check name",
+			search: "page_fault",
+			expected: []NameMatch{
+				{ErrorInfo: ErrorInfo{Code: 0x00000050, Name: "PAGE_FAULT_IN_NONPAGED_AREA", Description: "Page fault."}, Kind: KindBugCheck},
+			},
+		},
+		{
+			name:     "restricted to other catalog",
+			search:   "E_ACCESSDENIED",
+			kinds:    []Kind{KindNTStatus, KindWin32Error},
+			expected: []NameMatch{},
+		},
+		{
+			name:     "non-existent name",
+			search:   "NONEXISTENT",
+			expected: []NameMatch{},
+		},
+		{
+			name:     "empty name",
+			search:   "  ",
+			expected: []NameMatch{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := repo.FindByName(tt.search, tt.kinds...)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Repo.FindByName(%q) = %v, expected %v", tt.search, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepo_FindByName_Unindexed(t *testing.T) {
+	repo := createFullTestRepo()
+
+	result := repo.FindByName("e_notimpl")
+	if len(result) != 1 || result[0].Code != 0x80004001 {
+		t.Errorf("Repo.FindByName(%q) on unindexed repo = %v, expected E_NOTIMPL", "e_notimpl", result)
+	}
+}
+
+func TestRepo_FindBugCheckName(t *testing.T) {
+	repo := New(newBugCheckCatalog(KindBugCheck.String(), createTestBugCheckRepo()))
+
+	tests := []struct {
+		search   string
+		expected []string
+	}{
+		{search: "IRQL_NOT_LESS_OR_EQUAL", expected: []string{"IRQL_NOT_LESS_OR_EQUAL"}},
+		{search: "kmode", expected: []string{"KMODE_EXCEPTION_NOT_HANDLED"}},
+		{search: "DRIVER_IRQL", expected: []string{"DRIVER_IRQL_NOT_LESS_OR_EQUAL"}},
+		{search: "NONEXISTENT", expected: []string{}},
+		{search: "", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			names := []string{}
+			for _, bugCheck := range repo.FindBugCheckName(tt.search) {
+				names = append(names, bugCheck.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Repo.FindBugCheckName(%q) = %v, expected %v", tt.search, names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	for kind, expected := range map[Kind]string{
+		KindBugCheck:   "bug check",
+		KindHResult:    "HRESULT",
+		KindWin32Error: "Win32 error",
+		KindNTStatus:   "NTSTATUS",
+		Kind(99):       "unknown",
+	} {
+		if result := kind.String(); result != expected {
+			t.Errorf("Kind(%d).String() = %q, expected %q", kind, result, expected)
+		}
+	}
+}
+
+func BenchmarkRepo_FindByName(b *testing.B) {
+	repo, err := Load("")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = repo.FindByName("STATUS_ACCESS_VIOLATION")
+	}
+}
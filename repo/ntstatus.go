@@ -2,7 +2,7 @@ package repo
 
 import (
 	"fmt"
-	"os"
+	"io/fs"
 
 	"github.com/dhrdlicka/errorbot/winerror"
 	"gopkg.in/yaml.v3"
@@ -13,8 +13,8 @@ type NTStatusRepo struct {
 	Codes      []ErrorInfo
 }
 
-func LoadNTStatuses(name string) (NTStatusRepo, error) {
-	file, err := os.ReadFile(name)
+func LoadNTStatuses(fsys fs.FS, name string) (NTStatusRepo, error) {
+	file, err := fs.ReadFile(fsys, name)
 
 	if err != nil {
 		return NTStatusRepo{}, err
@@ -35,22 +35,29 @@ func (repo NTStatusRepo) FindCode(code uint32) []ErrorInfo {
 }
 
 func (repo Repo) FindNTStatus(code uint32) []ErrorInfo {
+	return repo.Find(KindNTStatus, code)
+}
+
+// findMappedNTStatus resolves NTSTATUS codes that wrap a Win32 error code.
+// Such codes, as well as HRESULTs with the N bit set, are not looked up in
+// the NTSTATUS catalogs.
+func (repo Repo) findMappedNTStatus(code uint32) ([]ErrorInfo, bool) {
 	s := winerror.NTStatus(code)
 
 	if s.N() {
 		// this is an NTSTATUS mapped into an HRESULT
-		return []ErrorInfo{}
+		return []ErrorInfo{}, true
 	} else if s.Sev() == winerror.STATUS_SEVERITY_ERROR && s.Facility() == winerror.FACILITY_NTWIN32 {
 		// this is a mapped Win32 error
-		win32ErrorMatches := repo.Win32Error.FindCode(uint32(s.Code()))
+		win32ErrorMatches := repo.FindWin32Error(uint32(s.Code()))
 
 		for i := range win32ErrorMatches {
 			win32ErrorMatches[i].Name = fmt.Sprintf("NTSTATUS_FROM_WIN32(%s)", win32ErrorMatches[i].Name)
 			win32ErrorMatches[i].Code = code
 		}
 
-		return win32ErrorMatches
+		return win32ErrorMatches, true
 	}
 
-	return repo.NTStatus.FindCode(code)
+	return nil, false
 }
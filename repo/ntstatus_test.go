@@ -25,13 +25,15 @@ func createTestNTStatusRepo() NTStatusRepo {
 
 // createTestRepoForNTStatus creates a complete test repository for NTSTATUS integration testing
 func createTestRepoForNTStatus() Repo {
-	return Repo{
-		NTStatus: createTestNTStatusRepo(),
-		Win32Error: Win32ErrorRepo{
+	ntStatuses := createTestNTStatusRepo()
+
+	return New(
+		newListCatalog(KindNTStatus.String(), KindNTStatus, ntStatuses.Codes, ntStatuses.Facilities),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{
 			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
 			{Code: 87, Name: "ERROR_INVALID_PARAMETER", Description: "The parameter is incorrect."},
-		},
-	}
+		}, nil),
+	)
 }
 
 func TestNTStatusRepo_FindCode(t *testing.T) {
@@ -321,10 +323,10 @@ func TestRepo_FindNTStatus_EdgeCases(t *testing.T) {
 	}
 
 	// Repository with empty sub-repositories
-	partialRepo := Repo{
-		NTStatus:   NTStatusRepo{Codes: []ErrorInfo{}},
-		Win32Error: Win32ErrorRepo{},
-	}
+	partialRepo := New(
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{}, nil),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{}, nil),
+	)
 
 	result = partialRepo.FindNTStatus(0xC0070005)
 	if len(result) != 0 {
@@ -382,11 +384,9 @@ func ExampleNTStatusRepo_FindCode() {
 }
 
 func ExampleRepo_FindNTStatus() {
-	repo := Repo{
-		Win32Error: Win32ErrorRepo{
-			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
-		},
-	}
+	repo := New(NewListCatalog("Win32 error", KindWin32Error, []ErrorInfo{
+		{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
+	}, nil))
 
 	// This will map Win32 error to NTSTATUS
 	matches := repo.FindNTStatus(0xC0070005)
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"io/fs"
+	"slices"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+	"gopkg.in/yaml.v3"
+)
+
+// NTStatusMapping pairs an NTSTATUS code with the Win32 error code
+// RtlNtStatusToDosError translates it to.
+type NTStatusMapping struct {
+	NTStatus   uint32 `yaml:"ntstatus"`
+	Win32Error uint32 `yaml:"win32error"`
+}
+
+type NTStatusMappingRepo []NTStatusMapping
+
+func LoadNTStatusMappings(fsys fs.FS, name string) (NTStatusMappingRepo, error) {
+	file, err := fs.ReadFile(fsys, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var repo NTStatusMappingRepo
+	err = yaml.Unmarshal(file, &repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+type ntStatusMappingIndex struct {
+	toWin32Error   map[uint32]uint32
+	fromWin32Error map[uint32][]uint32
+}
+
+func newNTStatusMappingIndex(mappings NTStatusMappingRepo) *ntStatusMappingIndex {
+	index := &ntStatusMappingIndex{
+		toWin32Error:   map[uint32]uint32{},
+		fromWin32Error: map[uint32][]uint32{},
+	}
+
+	for _, mapping := range mappings {
+		if _, ok := index.toWin32Error[mapping.NTStatus]; ok {
+			continue
+		}
+
+		index.toWin32Error[mapping.NTStatus] = mapping.Win32Error
+		index.fromWin32Error[mapping.Win32Error] = append(index.fromWin32Error[mapping.Win32Error], mapping.NTStatus)
+	}
+
+	for _, statuses := range index.fromWin32Error {
+		slices.Sort(statuses)
+	}
+
+	return index
+}
+
+// MapNTStatus returns the Win32 error code an NTSTATUS code translates to.
+// NTSTATUS_FROM_WIN32 codes map back to the Win32 error they wrap even if the
+// mapping table does not list them.
+func (repo Repo) MapNTStatus(code uint32) (uint32, bool) {
+	if win32Error, ok := repo.indexes().ntStatusMapping.toWin32Error[code]; ok {
+		return win32Error, true
+	}
+
+	status := winerror.NTStatus(code)
+
+	if status.Sev() == winerror.STATUS_SEVERITY_ERROR && status.Facility() == winerror.FACILITY_NTWIN32 {
+		return uint32(status.Code()), true
+	}
+
+	return 0, false
+}
+
+// MappedNTStatuses returns the NTSTATUS codes the mapping table translates to
+// the given Win32 error code, in ascending order.
+func (repo Repo) MappedNTStatuses(code uint32) []uint32 {
+	return slices.Clone(repo.indexes().ntStatusMapping.fromWin32Error[code])
+}
@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func createTestMappingRepo() Repo {
+	repo := Repo{
+		NTStatusMapping: NTStatusMappingRepo{
+			{NTStatus: 0xC0000022, Win32Error: 5},
+			{NTStatus: 0xC0000005, Win32Error: 998},
+			{NTStatus: 0xC00000BA, Win32Error: 5},
+			{NTStatus: 0xC0000056, Win32Error: 5},
+			// later duplicates are ignored
+			{NTStatus: 0xC0000022, Win32Error: 1},
+		},
+	}
+	repo.BuildIndex()
+
+	return repo
+}
+
+func TestLoadNTStatusMappings(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ntstatusmap.yml": {Data: []byte("- ntstatus: 0xC0000022\n  win32error: 5\n")},
+	}
+
+	mappings, err := LoadNTStatusMappings(fsys, "ntstatusmap.yml")
+
+	if err != nil {
+		t.Fatalf("LoadNTStatusMappings() unexpected error: %v", err)
+	}
+
+	expected := NTStatusMappingRepo{{NTStatus: 0xC0000022, Win32Error: 5}}
+
+	if !reflect.DeepEqual(mappings, expected) {
+		t.Errorf("LoadNTStatusMappings() = %+v, expected %+v", mappings, expected)
+	}
+
+	if _, err := LoadNTStatusMappings(fsys, "missing.yml"); err == nil {
+		t.Error("LoadNTStatusMappings() of a missing file expected error")
+	}
+}
+
+func TestRepo_MapNTStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected uint32
+		ok       bool
+	}{
+		{name: "first mapping wins", code: 0xC0000022, expected: 5, ok: true},
+		{name: "mapped", code: 0xC0000005, expected: 998, ok: true},
+		{name: "NTSTATUS_FROM_WIN32", code: 0xC007007E, expected: 126, ok: true},
+		{name: "not mapped", code: 0xC0000001, expected: 0, ok: false},
+		{name: "FACILITY_NTWIN32 warning", code: 0x8007007E, expected: 0, ok: false},
+	}
+
+	repo := createTestMappingRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := repo.MapNTStatus(tt.code)
+
+			if result != tt.expected || ok != tt.ok {
+				t.Errorf("MapNTStatus(0x%08X) = %d, %v, expected %d, %v", tt.code, result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRepo_MappedNTStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected []uint32
+	}{
+		{name: "several statuses", code: 5, expected: []uint32{0xC0000022, 0xC0000056, 0xC00000BA}},
+		{name: "single status", code: 998, expected: []uint32{0xC0000005}},
+		{name: "ignored duplicate", code: 1, expected: nil},
+		{name: "not mapped", code: 2, expected: nil},
+	}
+
+	repo := createTestMappingRepo()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := repo.MappedNTStatuses(tt.code)
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("MappedNTStatuses(%d) = %#v, expected %#v", tt.code, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad_NTStatusMappings(t *testing.T) {
+	repo, err := Load("")
+
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if code, ok := repo.MapNTStatus(0xC0000022); !ok || code != 5 {
+		t.Errorf("MapNTStatus(STATUS_ACCESS_DENIED) = %d, %v, expected 5, true", code, ok)
+	}
+}
@@ -1,43 +1,179 @@
 package repo
 
+import (
+	"io/fs"
+	"strings"
+)
+
 type Repo struct {
-	NTStatus   NTStatusRepo
-	HResult    HResultRepo
-	Win32Error Win32ErrorRepo
-	BugCheck   BugCheckRepo
+	// NTStatusMapping maps NTSTATUS codes to the Win32 errors they are
+	// reported as
+	NTStatusMapping NTStatusMappingRepo
+
+	// Manifest describes the files the repo was loaded from
+	Manifest Manifest
+
+	catalogs Registry
+	index    *repoIndex
 }
 
-func Load() (Repo, error) {
-	var err error
+// New creates an indexed repo from the given catalogs.
+func New(catalogs ...Catalog) Repo {
+	repo := Repo{catalogs: catalogs}
+	repo.BuildIndex()
+
+	return repo
+}
+
+// Load loads the embedded catalogs. If dataDir is not empty, catalog files
+// found in it are used instead of their embedded counterparts. The embedded
+// files are checked against the checksums in catalogs.yml, the ones from the
+// data directory are marked as overridden in the manifest instead.
+func Load(dataDir string) (Repo, error) {
+	return LoadFS(DataFS(dataDir))
+}
 
-	ntStatuses, err := LoadNTStatuses("yaml/ntstatus.yml")
+func LoadFS(fsys fs.FS) (Repo, error) {
+	manifest, err := LoadManifest(fsys, "catalogs.yml")
 
 	if err != nil {
 		return Repo{}, err
 	}
 
-	hResults, err := LoadHResults("yaml/hresult.yml")
+	var repo Repo
 
-	if err != nil {
-		return Repo{}, err
+	for i := range manifest {
+		if err := manifest[i].load(&repo, fsys); err != nil {
+			return Repo{}, err
+		}
 	}
 
-	win32Errors, err := LoadWin32Errors("yaml/win32error.yml")
+	repo.Manifest = manifest
 
-	if err != nil {
-		return Repo{}, err
+	repo.BuildIndex()
+
+	return repo, nil
+}
+
+// Register adds a catalog to the repo and indexes the repo again. Catalogs
+// are reported in the order they were registered in.
+func (repo *Repo) Register(catalog Catalog) {
+	repo.register(catalog)
+	repo.BuildIndex()
+}
+
+// register adds a catalog without indexing the repo, for loading several
+// catalogs before indexing them all at once.
+func (repo *Repo) register(catalog Catalog) {
+	repo.catalogs = append(repo.catalogs, catalog)
+	repo.index = nil
+}
+
+// Catalogs returns the catalogs of the repo in the order they are reported
+// in.
+func (repo Repo) Catalogs() Registry {
+	if repo.index != nil {
+		return repo.index.catalogs
+	}
+
+	return repo.catalogs
+}
+
+// FacilityName returns the name of a facility of codes of the given kind from
+// the first catalog of that kind that names it.
+func (repo Repo) FacilityName(kind Kind, facility uint16) (string, bool) {
+	for _, catalog := range repo.Catalogs().OfKind(kind) {
+		if name, ok := catalog.Facilities()[facility]; ok {
+			return name, true
+		}
 	}
 
-	bugChecks, err := LoadBugChecks("yaml/bugcheck.yml")
+	return "", false
+}
 
-	if err != nil {
-		return Repo{}, err
+// FindFacility returns the facility with the given name, e.g. FACILITY_WIN32,
+// from the first catalog that names it.
+func (repo Repo) FindFacility(name string) (uint16, bool) {
+	for _, catalog := range repo.Catalogs() {
+		for facility, facilityName := range catalog.Facilities() {
+			if strings.EqualFold(facilityName, name) {
+				return facility, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// Find looks up a code in the catalogs of the given kind. Codes that wrap a
+// code of another kind, such as HRESULT_FROM_WIN32 codes, are looked up as
+// the code they wrap.
+func (repo Repo) Find(kind Kind, code uint32) []ErrorInfo {
+	if matches, ok := repo.findMapped(kind, code); ok {
+		return matches
+	}
+
+	return repo.findCode(kind, code)
+}
+
+// findCode returns the entries with the given code in the catalogs of the
+// given kind, without resolving mapped codes like Find does.
+func (repo Repo) findCode(kind Kind, code uint32) []ErrorInfo {
+	matches := []ErrorInfo{}
+
+	for _, catalog := range repo.Catalogs().OfKind(kind) {
+		matches = append(matches, catalog.FindCode(code)...)
+	}
+
+	return matches
+}
+
+// findMapped resolves codes of the given kind that wrap a code of another
+// kind, such as HRESULT_FROM_WIN32 codes.
+func (repo Repo) findMapped(kind Kind, code uint32) ([]ErrorInfo, bool) {
+	switch kind {
+	case KindHResult:
+		return repo.findMappedHResult(code)
+	case KindNTStatus:
+		return repo.findMappedNTStatus(code)
+	}
+
+	return nil, false
+}
+
+// CatalogMatch holds the entries a catalog has for a code.
+type CatalogMatch struct {
+	Catalog Catalog
+	Matches []ErrorInfo
+}
+
+// Lookup looks up a code in every catalog of the repo, in registry order.
+// Mapped codes are resolved like Find does and reported under the first
+// catalog of their kind. Catalogs without matches are left out.
+func (repo Repo) Lookup(code uint32) []CatalogMatch {
+	results := []CatalogMatch{}
+	mapped := map[Kind]bool{}
+
+	for _, catalog := range repo.Catalogs() {
+		kind := catalog.Kind()
+
+		var matches []ErrorInfo
+
+		if found, ok := repo.findMapped(kind, code); ok {
+			if mapped[kind] {
+				continue
+			}
+
+			mapped[kind] = true
+			matches = found
+		} else {
+			matches = catalog.FindCode(code)
+		}
+
+		if len(matches) > 0 {
+			results = append(results, CatalogMatch{Catalog: catalog, Matches: matches})
+		}
 	}
 
-	return Repo{
-		NTStatus:   ntStatuses,
-		HResult:    hResults,
-		Win32Error: win32Errors,
-		BugCheck:   bugChecks,
-	}, nil
+	return results
 }
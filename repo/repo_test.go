@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/dhrdlicka/errorbot/winerror"
@@ -8,38 +10,38 @@ import (
 
 // createFullTestRepo creates a comprehensive test repository for integration testing
 func createFullTestRepo() Repo {
-	return Repo{
-		NTStatus: NTStatusRepo{
-			Facilities: map[uint16]string{
-				0: "FACILITY_NTWIN32",
-				1: "FACILITY_RPC",
-			},
-			Codes: []ErrorInfo{
-				{Code: 0x00000000, Name: "STATUS_SUCCESS", Description: "Success."},
-				{Code: 0xC0000001, Name: "STATUS_UNSUCCESSFUL", Description: "Unsuccessful."},
-				{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "Access denied."},
-			},
-		},
-		HResult: HResultRepo{
-			Facilities: map[uint16]string{
-				0: "FACILITY_NULL",
-				7: "FACILITY_WIN32",
-			},
-			Codes: []ErrorInfo{
-				{Code: 0x00000000, Name: "S_OK", Description: "Success."},
-				{Code: 0x80004001, Name: "E_NOTIMPL", Description: "Not implemented."},
-				{Code: 0x80070005, Name: "E_ACCESSDENIED", Description: "Access denied."},
-			},
-		},
-		Win32Error: Win32ErrorRepo{
+	return New(createFullTestCatalogs()...)
+}
+
+// createFullTestCatalogs creates the catalogs of createFullTestRepo, which
+// are not indexed until a repo is created from them
+func createFullTestCatalogs() Registry {
+	return Registry{
+		newBugCheckCatalog(KindBugCheck.String(), BugCheckRepo{
+			{Code: 0x0000000A, Name: "IRQL_NOT_LESS_OR_EQUAL", Description: "IRQL error."},
+			{Code: 0x00000050, Name: "PAGE_FAULT_IN_NONPAGED_AREA", Description: "Page fault."},
+		}),
+		newListCatalog(KindHResult.String(), KindHResult, []ErrorInfo{
+			{Code: 0x00000000, Name: "S_OK", Description: "Success."},
+			{Code: 0x80004001, Name: "E_NOTIMPL", Description: "Not implemented."},
+			{Code: 0x80070005, Name: "E_ACCESSDENIED", Description: "Access denied."},
+		}, map[uint16]string{
+			0: "FACILITY_NULL",
+			7: "FACILITY_WIN32",
+		}),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{
 			{Code: 0, Name: "ERROR_SUCCESS", Description: "Success."},
 			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
 			{Code: 87, Name: "ERROR_INVALID_PARAMETER", Description: "Invalid parameter."},
-		},
-		BugCheck: BugCheckRepo{
-			{Code: 0x0000000A, Name: "IRQL_NOT_LESS_OR_EQUAL", Description: "IRQL error."},
-			{Code: 0x00000050, Name: "PAGE_FAULT_IN_NONPAGED_AREA", Description: "Page fault."},
-		},
+		}, nil),
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{
+			{Code: 0x00000000, Name: "STATUS_SUCCESS", Description: "Success."},
+			{Code: 0xC0000001, Name: "STATUS_UNSUCCESSFUL", Description: "Unsuccessful."},
+			{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "Access denied."},
+		}, map[uint16]string{
+			0: "FACILITY_NTWIN32",
+			1: "FACILITY_RPC",
+		}),
 	}
 }
 
@@ -275,33 +277,37 @@ func TestRepo_Integration_RepositoryStructure(t *testing.T) {
 	repo := createFullTestRepo()
 
 	t.Run("repository completeness", func(t *testing.T) {
-		// Verify all sub-repositories are populated
-		if len(repo.NTStatus.Codes) == 0 {
-			t.Error("NTStatus repository should not be empty")
-		}
-		if len(repo.HResult.Codes) == 0 {
-			t.Error("HResult repository should not be empty")
-		}
-		if len(repo.Win32Error) == 0 {
-			t.Error("Win32Error repository should not be empty")
-		}
-		if len(repo.BugCheck) == 0 {
-			t.Error("BugCheck repository should not be empty")
+		// Verify every kind has a populated catalog
+		for _, kind := range Kinds {
+			if len(catalogEntries(repo, kind)) == 0 {
+				t.Errorf("%s catalog should not be empty", kind)
+			}
 		}
 	})
 
 	t.Run("facility mappings", func(t *testing.T) {
 		// Verify facility mappings exist
-		if len(repo.NTStatus.Facilities) == 0 {
-			t.Error("NTStatus facilities should not be empty")
-		}
-		if len(repo.HResult.Facilities) == 0 {
-			t.Error("HResult facilities should not be empty")
+		for _, kind := range []Kind{KindNTStatus, KindHResult} {
+			if len(repo.Catalogs().OfKind(kind)[0].Facilities()) == 0 {
+				t.Errorf("%s facilities should not be empty", kind)
+			}
 		}
 	})
 }
 
 // Helper functions
+
+// catalogEntries returns the entries of the first catalog of the given kind
+func catalogEntries(repo Repo, kind Kind) []ErrorInfo {
+	catalogs := repo.Catalogs().OfKind(kind)
+
+	if len(catalogs) == 0 {
+		return nil
+	}
+
+	return catalogs[0].(*ListCatalog).Entries()
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) &&
 		(s == substr ||
@@ -347,11 +353,9 @@ func BenchmarkRepo_Integration_AllMethods(b *testing.B) {
 
 // Example tests
 func ExampleRepo() {
-	repo := Repo{
-		Win32Error: Win32ErrorRepo{
-			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
-		},
-	}
+	repo := New(NewListCatalog("Win32 error", KindWin32Error, []ErrorInfo{
+		{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access denied."},
+	}, nil))
 
 	// HRESULT with Win32 mapping
 	matches := repo.FindHResult(0x80070005)
@@ -359,3 +363,62 @@ func ExampleRepo() {
 		println(matches[0].Name) // HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)
 	}
 }
+
+func TestLoad(t *testing.T) {
+	repo, err := Load("")
+
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	// every entry but the NTSTATUS mapping holds a catalog
+	if len(repo.Catalogs()) != len(repo.Manifest)-1 {
+		t.Errorf("Load() registered %d catalogs for %d manifest entries", len(repo.Catalogs()), len(repo.Manifest))
+	}
+
+	for _, catalog := range repo.Catalogs() {
+		if len(catalog.(*ListCatalog).Entries()) == 0 {
+			t.Errorf("Load() returned an empty %s catalog", catalog.Name())
+		}
+	}
+
+	if matches := repo.FindWin32Error(5); len(matches) == 0 || matches[0].Name != "ERROR_ACCESS_DENIED" {
+		t.Errorf("Load() catalog lookup of 5 = %v, expected ERROR_ACCESS_DENIED", matches)
+	}
+
+	if matches := repo.FindHResult(0x8024402C); len(matches) == 0 || matches[0].Name != "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED" {
+		t.Errorf("Load() catalog lookup of 0x8024402C = %v, expected WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", matches)
+	}
+
+	for _, entry := range repo.Manifest {
+		if entry.SHA256 == "" || entry.Source == "" || entry.Entries == 0 {
+			t.Errorf("Load() manifest entry %+v lacks a checksum, source or entries", entry)
+		}
+	}
+}
+
+// Files in the data directory are not held to the checksums of the embedded
+// ones, they are reported as overridden instead.
+func TestLoad_DataDirOverride(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "win32error.yml"), []byte("- code: 5\n  name: ERROR_OVERRIDDEN\n  description: Overridden.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := Load(dir)
+
+	if err != nil {
+		t.Fatalf("Load(%q) unexpected error: %v", dir, err)
+	}
+
+	if matches := repo.FindWin32Error(5); len(matches) != 1 || matches[0].Name != "ERROR_OVERRIDDEN" {
+		t.Errorf("FindWin32Error(5) = %v, expected ERROR_OVERRIDDEN", matches)
+	}
+
+	for _, entry := range repo.Manifest {
+		if expected := entry.File == "win32error.yml"; entry.Overridden != expected {
+			t.Errorf("manifest entry %s overridden = %v, expected %v", entry.File, entry.Overridden, expected)
+		}
+	}
+}
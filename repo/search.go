@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// BM25 ranking parameters
+const (
+	searchK1 = 1.2
+	searchB  = 0.75
+)
+
+// searchStopWords are left out of the index as they appear in most of the
+// messages and say nothing about them.
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "were": true,
+	"which": true, "with": true,
+}
+
+// SearchResult is a catalog entry found by searching its description.
+type SearchResult struct {
+	ErrorInfo
+	Kind  Kind
+	Score float64
+}
+
+type searchDocument struct {
+	// catalog is the position of the catalog in the registry the index was
+	// built from
+	catalog  int
+	position int
+	length   int
+}
+
+type searchPosting struct {
+	document  int
+	frequency int
+}
+
+// searchIndex is an inverted index over the descriptions of all catalogs.
+type searchIndex struct {
+	documents     []searchDocument
+	postings      map[string][]searchPosting
+	averageLength float64
+}
+
+// Tokenize splits text into lower-case words, leaving out stop words and
+// single characters such as the ones in %1 insert placeholders.
+func Tokenize(text string) []string {
+	tokens := []string{}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if len(word) > 1 && !searchStopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+
+	return tokens
+}
+
+func (index *searchIndex) add(catalog int, position int, texts ...string) {
+	document := len(index.documents)
+	frequencies := map[string]int{}
+	length := 0
+
+	for _, text := range texts {
+		for _, token := range Tokenize(text) {
+			frequencies[token]++
+			length++
+		}
+	}
+
+	if length == 0 {
+		return
+	}
+
+	index.documents = append(index.documents, searchDocument{
+		catalog:  catalog,
+		position: position,
+		length:   length,
+	})
+
+	for token, frequency := range frequencies {
+		index.postings[token] = append(index.postings[token], searchPosting{
+			document:  document,
+			frequency: frequency,
+		})
+	}
+}
+
+// newSearchIndex indexes the catalogs of the registry backed by lists.
+func newSearchIndex(catalogs Registry) *searchIndex {
+	index := &searchIndex{
+		postings: map[string][]searchPosting{},
+	}
+
+	for i, catalog := range catalogs {
+		list, ok := catalog.(*ListCatalog)
+
+		if !ok {
+			continue
+		}
+
+		for position := range list.entries {
+			index.add(i, position, list.searchTexts(position)...)
+		}
+	}
+
+	total := 0
+
+	for _, document := range index.documents {
+		total += document.length
+	}
+
+	if len(index.documents) > 0 {
+		index.averageLength = float64(total) / float64(len(index.documents))
+	}
+
+	return index
+}
+
+// Search looks up entries whose description matches the words of the query
+// in the catalogs of the given kinds, or in all catalogs if no kinds are
+// given. Results are ranked by relevance using BM25, best match first.
+func (repo Repo) Search(query string, kinds ...Kind) []SearchResult {
+	index := repo.indexes()
+	results := index.search.find(index.catalogs, query, kinds)
+
+	// catalogs not backed by lists are not in the index and search
+	// themselves
+	for _, catalog := range index.catalogs {
+		if _, ok := catalog.(*ListCatalog); ok {
+			continue
+		}
+
+		if len(kinds) == 0 || slices.Contains(kinds, catalog.Kind()) {
+			results = append(results, catalog.Search(query)...)
+		}
+	}
+
+	sortSearchResults(results)
+
+	return results
+}
+
+// find scores the documents of the index from the given registry against
+// the query, leaving out catalogs not of the given kinds if any are given.
+func (index *searchIndex) find(catalogs Registry, query string, kinds []Kind) []SearchResult {
+	results := []SearchResult{}
+	scores := map[int]float64{}
+
+	for _, token := range slices.Compact(slices.Sorted(slices.Values(Tokenize(query)))) {
+		postings := index.postings[token]
+
+		if len(postings) == 0 {
+			continue
+		}
+
+		n := float64(len(index.documents))
+		idf := math.Log(1 + (n-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+
+		for _, posting := range postings {
+			document := index.documents[posting.document]
+
+			if len(kinds) > 0 && !slices.Contains(kinds, catalogs[document.catalog].Kind()) {
+				continue
+			}
+
+			frequency := float64(posting.frequency)
+			norm := searchK1 * (1 - searchB + searchB*float64(document.length)/index.averageLength)
+
+			scores[posting.document] += idf * frequency * (searchK1 + 1) / (frequency + norm)
+		}
+	}
+
+	for i, score := range scores {
+		document := index.documents[i]
+		catalog := catalogs[document.catalog].(*ListCatalog)
+
+		results = append(results, SearchResult{
+			ErrorInfo: catalog.entries[document.position],
+			Kind:      catalog.kind,
+			Score:     score,
+		})
+	}
+
+	sortSearchResults(results)
+
+	return results
+}
+
+func sortSearchResults(results []SearchResult) {
+	slices.SortFunc(results, func(a, b SearchResult) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		if a.Kind != b.Kind {
+			return int(a.Kind) - int(b.Kind)
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "The system cannot find the file specified.", expected: []string{"system", "cannot", "find", "file", "specified"}},
+		{input: "The instruction at 0x%p referenced memory at 0x%p.", expected: []string{"instruction", "0x", "referenced", "memory", "0x"}},
+		{input: "{Application Error} %1", expected: []string{"application", "error"}},
+		{input: "", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := Tokenize(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Tokenize(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func createTestSearchRepo() Repo {
+	repo := New(
+		newBugCheckCatalog(KindBugCheck.String(), BugCheckRepo{
+			{Code: 0x7B, Name: "INACCESSIBLE_BOOT_DEVICE", Description: "The operating system lost access to the system partition.", Parameters: []string{"The address of a UNICODE_STRING structure"}},
+		}),
+		newListCatalog(KindWin32Error.String(), KindWin32Error, []ErrorInfo{
+			{Code: 2, Name: "ERROR_FILE_NOT_FOUND", Description: "The system cannot find the file specified."},
+			{Code: 3, Name: "ERROR_PATH_NOT_FOUND", Description: "The system cannot find the path specified."},
+			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
+		}, nil),
+		newListCatalog(KindNTStatus.String(), KindNTStatus, []ErrorInfo{
+			{Code: 0xC0000022, Name: "STATUS_ACCESS_DENIED", Description: "A process has requested access to an object but has not been granted those access rights."},
+			{Code: 0xC0000034, Name: "STATUS_OBJECT_NAME_NOT_FOUND", Description: "Object Name not found."},
+		}, nil),
+	)
+
+	return repo
+}
+
+func TestRepo_Search(t *testing.T) {
+	repo := createTestSearchRepo()
+
+	tests := []struct {
+		name     string
+		query    string
+		kinds    []Kind
+		expected []string
+	}{
+		{
+			name:     "full message ranks the exact entry first",
+			query:    "the system cannot find the file specified",
+			expected: []string{"ERROR_FILE_NOT_FOUND", "ERROR_PATH_NOT_FOUND", "INACCESSIBLE_BOOT_DEVICE"},
+		},
+		{
+			name:     "case-insensitive single word",
+			query:    "DENIED",
+			expected: []string{"ERROR_ACCESS_DENIED"},
+		},
+		{
+			name:     "short description ranks first",
+			query:    "access",
+			expected: []string{"ERROR_ACCESS_DENIED", "STATUS_ACCESS_DENIED", "INACCESSIBLE_BOOT_DEVICE"},
+		},
+		{
+			name:     "bug check parameters are searched",
+			query:    "unicode_string",
+			expected: []string{"INACCESSIBLE_BOOT_DEVICE"},
+		},
+		{
+			name:     "restricted to catalog",
+			query:    "access",
+			kinds:    []Kind{KindNTStatus},
+			expected: []string{"STATUS_ACCESS_DENIED"},
+		},
+		{
+			name:     "only stop words",
+			query:    "the of",
+			expected: []string{},
+		},
+		{
+			name:     "no match",
+			query:    "printer",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{}
+			for _, result := range repo.Search(tt.query, tt.kinds...) {
+				names = append(names, result.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Repo.Search(%q) = %v, expected %v", tt.query, names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepo_Search_Kinds(t *testing.T) {
+	repo := createTestSearchRepo()
+
+	results := repo.Search("object name")
+	if len(results) == 0 || results[0].Kind != KindNTStatus || results[0].Code != 0xC0000034 {
+		t.Errorf("Repo.Search(%q) = %v, expected STATUS_OBJECT_NAME_NOT_FOUND first", "object name", results)
+	}
+}
+
+func TestRepo_Search_Loaded(t *testing.T) {
+	repo, err := Load("")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := repo.Search("the system cannot find the file specified")
+	if len(results) == 0 || results[0].Name != "ERROR_FILE_NOT_FOUND" {
+		t.Errorf("Repo.Search() best match = %v, expected ERROR_FILE_NOT_FOUND", results[:min(len(results), 3)])
+	}
+}
+
+func BenchmarkRepo_Search(b *testing.B) {
+	repo, err := Load("")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = repo.Search("the system cannot find the file specified")
+	}
+}
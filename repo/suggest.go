@@ -0,0 +1,78 @@
+package repo
+
+import "strings"
+
+// Suggest returns up to limit entries whose symbolic name or hexadecimal code
+// starts with the given prefix in the catalogs of the given kinds, or in all
+// catalogs if no kinds are given. Name matches come before code matches.
+func (repo Repo) Suggest(prefix string, limit int, kinds ...Kind) []NameMatch {
+	matches := []NameMatch{}
+	prefix = strings.TrimSpace(prefix)
+
+	if prefix == "" || limit <= 0 {
+		return matches
+	}
+
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+
+	// entries are told apart by kind, code and name, as their translations
+	// make them incomparable
+	type suggestion struct {
+		kind Kind
+		code uint32
+		name string
+	}
+
+	catalogs := repo.indexes().catalogs
+	seen := map[suggestion]bool{}
+
+	for _, byCode := range []bool{false, true} {
+		for _, kind := range kinds {
+			for _, info := range suggestEntries(catalogs.OfKind(kind), prefix, byCode) {
+				match := NameMatch{
+					ErrorInfo: info,
+					Kind:      kind,
+				}
+
+				key := suggestion{kind, info.Code, info.Name}
+
+				if seen[key] {
+					continue
+				}
+
+				seen[key] = true
+				matches = append(matches, match)
+
+				if len(matches) == limit {
+					return matches
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// suggestEntries returns the entries of the catalogs whose name, or
+// hexadecimal code if byCode is set, starts with the given prefix. Catalogs
+// not backed by lists are only asked for names.
+func suggestEntries(catalogs Registry, prefix string, byCode bool) []ErrorInfo {
+	entries := []ErrorInfo{}
+
+	for _, catalog := range catalogs {
+		list, ok := catalog.(*ListCatalog)
+
+		switch {
+		case ok && byCode:
+			entries = append(entries, pick(list.entries, list.codeIndex().CodePrefix(prefix))...)
+		case ok:
+			entries = append(entries, pick(list.entries, list.codeIndex().Prefix(prefix))...)
+		case !byCode:
+			entries = append(entries, catalog.FindName(prefix)...)
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepo_Suggest(t *testing.T) {
+	repo := createFullTestRepo()
+
+	tests := []struct {
+		name     string
+		prefix   string
+		limit    int
+		kinds    []Kind
+		expected []string
+	}{
+		{
+			name:     "name prefix across catalogs",
+			prefix:   "e_",
+			limit:    25,
+			expected: []string{"E_ACCESSDENIED", "E_NOTIMPL"},
+		},
+		{
+			name:     "hex code prefix",
+			prefix:   "0xC00000",
+			limit:    25,
+			expected: []string{"STATUS_UNSUCCESSFUL", "STATUS_ACCESS_DENIED"},
+		},
+		{
+			name:     "hex code prefix with leading zeros",
+			prefix:   "0x0000000A",
+			limit:    25,
+			expected: []string{"IRQL_NOT_LESS_OR_EQUAL"},
+		},
+		{
+			name:     "hex code prefix without 0x",
+			prefix:   "5",
+			limit:    25,
+			expected: []string{"PAGE_FAULT_IN_NONPAGED_AREA", "ERROR_ACCESS_DENIED", "ERROR_INVALID_PARAMETER"},
+		},
+		{
+			name:     "restricted to catalog",
+			prefix:   "0",
+			limit:    25,
+			kinds:    []Kind{KindNTStatus},
+			expected: []string{"STATUS_SUCCESS"},
+		},
+		{
+			name:     "limit",
+			prefix:   "STATUS",
+			limit:    2,
+			expected: []string{"STATUS_ACCESS_DENIED", "STATUS_SUCCESS"},
+		},
+		{
+			name:     "empty prefix",
+			prefix:   "",
+			limit:    25,
+			expected: []string{},
+		},
+		{
+			name:     "no match",
+			prefix:   "XYZ",
+			limit:    25,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{}
+			for _, match := range repo.Suggest(tt.prefix, tt.limit, tt.kinds...) {
+				names = append(names, match.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Repo.Suggest(%q, %d) = %v, expected %v", tt.prefix, tt.limit, names, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkRepo_Suggest(b *testing.B) {
+	repo, err := Load("")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = repo.Suggest("STATUS_ACC", 25)
+		_ = repo.Suggest("0x8007", 25)
+	}
+}
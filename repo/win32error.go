@@ -1,15 +1,15 @@
 package repo
 
 import (
-	"os"
+	"io/fs"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Win32ErrorRepo []ErrorInfo
 
-func LoadWin32Errors(name string) (Win32ErrorRepo, error) {
-	file, err := os.ReadFile(name)
+func LoadWin32Errors(fsys fs.FS, name string) (Win32ErrorRepo, error) {
+	file, err := fs.ReadFile(fsys, name)
 
 	if err != nil {
 		return nil, err
@@ -30,5 +30,5 @@ func (repo Win32ErrorRepo) FindCode(code uint32) []ErrorInfo {
 }
 
 func (repo Repo) FindWin32Error(code uint32) []ErrorInfo {
-	return repo.Win32Error.FindCode(code)
+	return repo.Find(KindWin32Error, code)
 }
@@ -3,6 +3,7 @@ package repo
 import (
 	"reflect"
 	"testing"
+	"testing/fstest"
 )
 
 // createTestWin32ErrorRepo creates a test repository with sample Win32 error data
@@ -71,9 +72,7 @@ func TestWin32ErrorRepo_FindCode(t *testing.T) {
 }
 
 func TestRepo_FindWin32Error(t *testing.T) {
-	repo := Repo{
-		Win32Error: createTestWin32ErrorRepo(),
-	}
+	repo := New(newListCatalog(KindWin32Error.String(), KindWin32Error, createTestWin32ErrorRepo(), nil))
 
 	tests := []struct {
 		name     string
@@ -256,7 +255,7 @@ func BenchmarkWin32ErrorRepo_FindCode_NotFound(b *testing.B) {
 }
 
 func BenchmarkRepo_FindWin32Error(b *testing.B) {
-	repo := Repo{Win32Error: createTestWin32ErrorRepo()}
+	repo := New(newListCatalog(KindWin32Error.String(), KindWin32Error, createTestWin32ErrorRepo(), nil))
 	
 	for i := 0; i < b.N; i++ {
 		_ = repo.FindWin32Error(5)
@@ -276,14 +275,33 @@ func ExampleWin32ErrorRepo_FindCode() {
 }
 
 func ExampleRepo_FindWin32Error() {
-	repo := Repo{
-		Win32Error: Win32ErrorRepo{
-			{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
-		},
-	}
+	repo := New(NewListCatalog("Win32 error", KindWin32Error, []ErrorInfo{
+		{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
+	}, nil))
 	
 	matches := repo.FindWin32Error(5)
 	if len(matches) > 0 {
 		println(matches[0].Name) // ERROR_ACCESS_DENIED
 	}
 }
+
+func TestLoadWin32Errors_Availability(t *testing.T) {
+	fsys := fstest.MapFS{
+		"win32error.yml": {Data: []byte("- code: 5\n  name: ERROR_ACCESS_DENIED\n  description: Access is denied.\n- code: 15700\n  name: ERROR_APPMODEL_NO_PACKAGE\n  description: The process has no package identity.\n  introduced: 10.0.19041.0\n")},
+	}
+
+	errors, err := LoadWin32Errors(fsys, "win32error.yml")
+
+	if err != nil {
+		t.Fatalf("LoadWin32Errors() unexpected error: %v", err)
+	}
+
+	expected := Win32ErrorRepo{
+		{Code: 5, Name: "ERROR_ACCESS_DENIED", Description: "Access is denied."},
+		{Code: 15700, Name: "ERROR_APPMODEL_NO_PACKAGE", Description: "The process has no package identity.", Introduced: "10.0.19041.0"},
+	}
+
+	if !reflect.DeepEqual(errors, expected) {
+		t.Errorf("LoadWin32Errors() = %+v, expected %+v", errors, expected)
+	}
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"io/fs"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+	"gopkg.in/yaml.v3"
+)
+
+// WindowsUpdateRepo holds the Windows Update Agent codes from wuerror.h, the
+// HRESULTs in FACILITY_WINDOWSUPDATE.
+type WindowsUpdateRepo []ErrorInfo
+
+func LoadWindowsUpdateErrors(fsys fs.FS, name string) (WindowsUpdateRepo, error) {
+	file, err := fs.ReadFile(fsys, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var repo WindowsUpdateRepo
+	err = yaml.Unmarshal(file, &repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (repo WindowsUpdateRepo) FindCode(code uint32) []ErrorInfo {
+	return FindCode(repo, code)
+}
+
+// windowsUpdateComponents names the components wuerror.h assigns the ranges
+// of FACILITY_WINDOWSUPDATE codes to, by the top nibble of the code.
+var windowsUpdateComponents = map[uint16]string{
+	0x0: "Windows Update Agent",
+	0x1: "Windows Installer",
+	0x2: "Update handler",
+	0x3: "User interface",
+	0x4: "Protocol talker",
+	0x5: "Redirector",
+	0x6: "Download manager",
+	0x7: "Offline scan",
+	0x8: "Data store",
+	0x9: "Inventory",
+	0xA: "Automatic Updates",
+	0xB: "Server-initiated healing",
+	0xC: "Driver utility",
+	0xD: "Setup and self-update",
+	0xE: "Expression evaluator",
+	0xF: "Reporter",
+}
+
+// WindowsUpdateComponent names the Windows Update Agent component an HRESULT
+// in FACILITY_WINDOWSUPDATE comes from, e.g. "Protocol talker" for the
+// WU_E_PT_* codes in 0x80244xxx.
+func WindowsUpdateComponent(code uint32) (string, bool) {
+	hr := winerror.HResult(code)
+
+	if hr.R() || hr.N() || hr.Facility() != winerror.FACILITY_WINDOWSUPDATE {
+		return "", false
+	}
+
+	component, ok := windowsUpdateComponents[hr.Code()>>12]
+
+	return component, ok
+}
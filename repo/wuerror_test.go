@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func createTestWindowsUpdateRepo() Repo {
+	repo := New(
+		newListCatalog(KindHResult.String(), KindHResult, []ErrorInfo{
+			{Code: 0x80004005, Name: "E_FAIL", Description: "Unspecified error"},
+		}, nil),
+		newListCatalog("Windows Update Agent", KindHResult, []ErrorInfo{
+			{Code: 0x80240001, Name: "WU_E_NO_SERVICE", Description: "Windows Update Agent was unable to provide the service."},
+			{Code: 0x8024402C, Name: "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", Description: "Same as ERROR_WINHTTP_NAME_NOT_RESOLVED - the proxy server or target server name cannot be resolved."},
+			{Code: 0x80246007, Name: "WU_E_DM_NOTDOWNLOADED", Description: "The update has not been downloaded."},
+		}, nil),
+	)
+
+	return repo
+}
+
+func TestLoadWindowsUpdateErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wuerror.yml": {Data: []byte("- code: 0x80240001\n  name: WU_E_NO_SERVICE\n  description: Windows Update Agent was unable to provide the service.\n")},
+	}
+
+	errors, err := LoadWindowsUpdateErrors(fsys, "wuerror.yml")
+
+	if err != nil {
+		t.Fatalf("LoadWindowsUpdateErrors() unexpected error: %v", err)
+	}
+
+	expected := WindowsUpdateRepo{{Code: 0x80240001, Name: "WU_E_NO_SERVICE", Description: "Windows Update Agent was unable to provide the service."}}
+
+	if !reflect.DeepEqual(errors, expected) {
+		t.Errorf("LoadWindowsUpdateErrors() = %+v, expected %+v", errors, expected)
+	}
+
+	if _, err := LoadWindowsUpdateErrors(fsys, "missing.yml"); err == nil {
+		t.Error("LoadWindowsUpdateErrors() of a missing file expected error")
+	}
+}
+
+func TestRepo_FindHResult_WindowsUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected []string
+	}{
+		{name: "Windows Update code", code: 0x8024402C, expected: []string{"WU_E_PT_WINHTTP_NAME_NOT_RESOLVED"}},
+		{name: "HRESULT catalog code", code: 0x80004005, expected: []string{"E_FAIL"}},
+		{name: "unknown Windows Update code", code: 0x8024FFFE, expected: []string{}},
+	}
+
+	indexed := createTestWindowsUpdateRepo()
+	unindexed := indexed
+	unindexed.index = nil
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, repo := range []Repo{indexed, unindexed} {
+				names := []string{}
+
+				for _, match := range repo.FindHResult(tt.code) {
+					names = append(names, match.Name)
+				}
+
+				if !reflect.DeepEqual(names, tt.expected) {
+					t.Errorf("FindHResult(0x%08X) = %v, expected %v (indexed: %v)", tt.code, names, tt.expected, repo.index != nil)
+				}
+			}
+		})
+	}
+}
+
+func TestRepo_WindowsUpdateByName(t *testing.T) {
+	repo := createTestWindowsUpdateRepo()
+
+	matches := repo.FindByName("wu_e_dm_notdownloaded", KindHResult)
+
+	if len(matches) != 1 || matches[0].Code != 0x80246007 || matches[0].Kind != KindHResult {
+		t.Errorf("FindByName(wu_e_dm_notdownloaded) = %+v, expected WU_E_DM_NOTDOWNLOADED", matches)
+	}
+
+	suggestions := repo.Suggest("WU_E_PT", 5, KindHResult)
+
+	if len(suggestions) != 1 || suggestions[0].Name != "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED" {
+		t.Errorf("Suggest(WU_E_PT) = %+v, expected WU_E_PT_WINHTTP_NAME_NOT_RESOLVED", suggestions)
+	}
+
+	results := repo.Search("proxy server name", KindHResult)
+
+	if len(results) == 0 || results[0].Name != "WU_E_PT_WINHTTP_NAME_NOT_RESOLVED" {
+		t.Errorf("Search(proxy server name) = %+v, expected WU_E_PT_WINHTTP_NAME_NOT_RESOLVED first", results)
+	}
+}
+
+func TestWindowsUpdateComponent(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected string
+		ok       bool
+	}{
+		{name: "common", code: 0x80240001, expected: "Windows Update Agent", ok: true},
+		{name: "success code", code: 0x00240005, expected: "Windows Update Agent", ok: true},
+		{name: "Windows Installer", code: 0x80241003, expected: "Windows Installer", ok: true},
+		{name: "update handler", code: 0x80242FFF, expected: "Update handler", ok: true},
+		{name: "protocol talker", code: 0x8024402C, expected: "Protocol talker", ok: true},
+		{name: "download manager", code: 0x80246007, expected: "Download manager", ok: true},
+		{name: "data store", code: 0x80248007, expected: "Data store", ok: true},
+		{name: "setup", code: 0x8024D00E, expected: "Setup and self-update", ok: true},
+		{name: "reporter", code: 0x8024FFFF, expected: "Reporter", ok: true},
+		{name: "server-initiated healing", code: 0x8024B001, expected: "Server-initiated healing", ok: true},
+		{name: "other facility", code: 0x80070005, expected: "", ok: false},
+		{name: "mapped NTSTATUS", code: 0xD0240001, expected: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := WindowsUpdateComponent(tt.code)
+
+			if result != tt.expected || ok != tt.ok {
+				t.Errorf("WindowsUpdateComponent(0x%08X) = %q, %v, expected %q, %v", tt.code, result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func ExampleWindowsUpdateComponent() {
+	component, _ := WindowsUpdateComponent(0x8024402C)
+	println(component) // Protocol talker
+}
@@ -1,17 +1,35 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/dhrdlicka/errorbot/eventlog"
 	"github.com/dhrdlicka/errorbot/repo"
+	"github.com/dhrdlicka/errorbot/updatelog"
 	"github.com/dhrdlicka/errorbot/util"
 )
 
-var value = flag.String("c", "", "`error code` in decimal or hexadecimal format [e.g. 1, -2147024894, 0x7B, C0000005]")
+var (
+	value   = flag.String("c", "", "`error code` in decimal or hexadecimal format or its symbolic name [e.g. 1, -2147024894, 0x7B, C0000005, 0n5, 0x80070005L, E_FAIL, HRESULT_FROM_WIN32(5)]")
+	query   = flag.String("s", "", "search error messages for a `query` instead of looking up a code [e.g. \"cannot find the file\"]")
+	extract = flag.String("x", "", "find and look up every error code in a text `file`, - for standard input")
+	line    = flag.String("b", "", "decode a bug check `line` from a blue screen, the event log or the debugger, - for standard input [e.g. \"STOP: 0x0000007E (0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740)\"]")
+	events  = flag.String("evtx-xml", "", "find and look up the error codes in events saved as XML from Event Viewer or with wevtutil qe /f:xml, `file` or - for standard input")
+	logFile = flag.String("log", "", "find the failures in a CBS.log, DISM.log or WindowsUpdate.log `file`, - for standard input")
+	convert = flag.Bool("convert", false, "show the code given with -c in every error namespace")
+	limit   = flag.Int("n", 10, "maximum `number` of search results")
+	dataDir = flag.String("d", os.Getenv("ERRORBOT_DATA_DIR"), "`directory` with catalog files overriding the embedded ones")
+	version = flag.Bool("version", false, "show the version and where the catalog data comes from")
+)
 
 func main() {
 	log.SetFlags(0)
@@ -19,54 +37,417 @@ func main() {
 
 	flag.Parse()
 
-	if *value == "" {
+	if *value == "" && *query == "" && *extract == "" && *line == "" && *events == "" && *logFile == "" && !*version {
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	codes, err := util.ParseCode(*value)
+	repoInstance, err := repo.Load(*dataDir)
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	repoInstance, err := repo.Load()
+	if *version {
+		printVersion(repoInstance)
+		return
+	}
 
-	if err != nil {
-		log.Fatal(err)
+	if *query != "" {
+		search(repoInstance)
+		return
 	}
 
-	repos := []struct {
-		findCode func(uint32) []repo.ErrorInfo
-		name     string
-	}{
-		{repoInstance.FindBugCheck, "bug check"},
-		{repoInstance.FindHResult, "HRESULT"},
-		{repoInstance.FindWin32Error, "Win32 error"},
-		{repoInstance.FindNTStatus, "NTSTATUS"},
+	if *extract != "" {
+		extractCodes(repoInstance)
+		return
 	}
 
-	found := false
+	if *line != "" {
+		decodeBugCheck(repoInstance)
+		return
+	}
 
-	for _, errorRepo := range repos {
-		matches := []repo.ErrorInfo{}
+	if *events != "" {
+		analyzeEvents(repoInstance)
+		return
+	}
+
+	if *logFile != "" {
+		analyzeLog(repoInstance)
+		return
+	}
 
+	interpretations, parseErr := interpretValue(repoInstance, *value)
+	codes := []uint32{}
+
+	for _, interpretation := range interpretations {
+		codes = append(codes, interpretation.Code)
+
+		fmt.Printf("Read as %s: 0x%08X\n", interpretation.Format, interpretation.Code)
+	}
+
+	if len(codes) > 0 {
+		fmt.Println()
+	}
+
+	if *convert {
+		convertCodes(repoInstance, codes, parseErr)
+		return
+	}
+
+	found := false
+	matches := map[repo.Kind][]repo.ErrorInfo{}
+
+	if parseErr != nil {
+		for _, match := range repoInstance.FindByName(*value) {
+			matches[match.Kind] = append(matches[match.Kind], match.ErrorInfo)
+		}
+	} else {
 		for _, code := range codes {
-			matches = append(matches, errorRepo.findCode(code)...)
+			for _, result := range repoInstance.Lookup(code) {
+				kind := result.Catalog.Kind()
+				matches[kind] = append(matches[kind], result.Matches...)
+			}
 		}
+	}
 
-		if len(matches) > 0 {
+	for _, kind := range repo.Kinds {
+		if len(matches[kind]) > 0 {
 			found = true
 
-			fmt.Printf("# Possible %s codes:\n\n%s\n", errorRepo.name, formatResults(matches))
+			fmt.Printf("# Possible %s codes:\n\n%s\n", kind, formatResults(matches[kind]))
 		}
 	}
 
 	if !found {
+		if parseErr != nil {
+			log.Fatalf("could not find error code %s: %v\n", *value, parseErr)
+		}
+
 		log.Fatalf("could not find error code %s (`0x%08X`)\n", *value, codes[0])
 	}
 }
 
+func convertCodes(repoInstance repo.Repo, codes []uint32, parseErr error) {
+	if parseErr != nil {
+		for _, match := range repoInstance.FindByName(*value) {
+			if !slices.Contains(codes, match.Code) {
+				codes = append(codes, match.Code)
+			}
+		}
+
+		if len(codes) == 0 {
+			log.Fatalf("could not find error code %s: %v\n", *value, parseErr)
+		}
+	}
+
+	for _, code := range codes {
+		fmt.Printf("# Conversions of 0x%08X (unsigned %d, signed %d):\n\n", code, code, int32(code))
+
+		for _, conversion := range repoInstance.Convert(code) {
+			fmt.Printf("%s: %s = 0x%08X (unsigned %d, signed %d)\n", conversion.Kind, conversion.Expression, conversion.Code, conversion.Code, int32(conversion.Code))
+
+			for _, match := range conversion.Matches {
+				fmt.Printf("> %s\n", match.Name)
+			}
+		}
+
+		fmt.Println()
+	}
+}
+
+func printVersion(repoInstance repo.Repo) {
+	fmt.Printf("lookup %s\n", util.Version())
+
+	if *dataDir != "" {
+		fmt.Printf("Catalog files from %s override the embedded ones\n", *dataDir)
+	}
+
+	for _, entry := range repoInstance.Manifest {
+		fmt.Printf("\n# %s\n", entry.Name)
+		fmt.Printf("File: %s\n", entry.File)
+		fmt.Printf("Entries: %d\n", entry.Entries)
+
+		for _, field := range [][2]string{{"Source", entry.Source}, {"SDK", entry.SDK}, {"Generated", entry.Generated}} {
+			if field[1] != "" {
+				fmt.Printf("%s: %s\n", field[0], field[1])
+			}
+		}
+
+		if entry.Overridden {
+			fmt.Printf("Overridden by %s, checksum not verified\n", filepath.Join(*dataDir, entry.File))
+		} else if entry.SHA256 != "" {
+			fmt.Printf("SHA-256: %s\n", entry.SHA256)
+		}
+	}
+}
+
+func search(repoInstance repo.Repo) {
+	results := repoInstance.Search(*query)
+
+	if len(results) == 0 {
+		log.Fatalf("could not find any error codes matching %s\n", *query)
+	}
+
+	fmt.Printf("# Search results for \"%s\" (%d):\n\n", *query, len(results))
+
+	for _, result := range results[:min(*limit, len(results))] {
+		fmt.Printf("`%s` (`0x%08X`, %s)\n", result.Name, result.Code, result.Kind)
+
+		for _, line := range strings.Split(result.Description, "\n") {
+			fmt.Printf("> %s\n", strings.TrimSpace(line))
+		}
+	}
+}
+
+func extractCodes(repoInstance repo.Repo) {
+	text, err := readInput(*extract)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	found := false
+
+	for _, match := range util.ExtractCodes(string(text)) {
+		// prefer the catalogs the context hints at, like the bot does
+		matches := findExtracted(repoInstance, match, match.Kinds)
+
+		if len(matches) == 0 {
+			matches = findExtracted(repoInstance, match, repo.Kinds)
+		}
+
+		if len(matches) > 0 {
+			found = true
+
+			fmt.Printf("# %s:\n\n%s\n", match.Text, formatResults(matches))
+		}
+	}
+
+	if !found {
+		log.Fatalf("could not find any known error codes in %s\n", *extract)
+	}
+}
+
+func decodeBugCheck(repoInstance repo.Repo) {
+	text := *line
+
+	// multi-line !analyze output is easier to pipe in than to quote
+	if text == "-" {
+		input, err := io.ReadAll(os.Stdin)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		text = string(input)
+	}
+
+	bugCheck, err := util.ParseBugCheckLine(text)
+
+	if err != nil {
+		log.Fatalf("could not read bug check line: %v\n", err)
+	}
+
+	fmt.Printf("Read bug check 0x%08X from the %s\n\n", bugCheck.Code, bugCheck.Format)
+
+	name := bugCheck.Name
+
+	if matches := repoInstance.FindBugCheckCode(bugCheck.Code); len(matches) > 0 {
+		name = matches[0].Name
+	} else if name == "" {
+		name = "Unknown bug check"
+	}
+
+	fmt.Printf("# %s (0x%08X)\n\n", name, bugCheck.Code)
+
+	printBugCheckParameters(repoInstance.DecodeBugCheck(bugCheck.Code, bugCheck.Parameters))
+}
+
+func printBugCheckParameters(parameters []repo.BugCheckParameter) {
+	for i, parameter := range parameters {
+		fmt.Printf("Parameter %d: 0x%X\n", i+1, parameter.Value)
+
+		if parameter.Description != "" {
+			fmt.Printf("  %s\n", strings.ReplaceAll(parameter.Description, "\n", "\n  "))
+		}
+
+		if parameter.Interpretation != "" {
+			fmt.Printf("  > %s\n", parameter.Interpretation)
+		}
+	}
+}
+
+func analyzeEvents(repoInstance repo.Repo) {
+	input, err := readInput(*events)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	parsed, parseErr := eventlog.Parse(bytes.NewReader(input))
+
+	if len(parsed) == 0 {
+		log.Fatalf("could not read events from %s: %v\n", *events, parseErr)
+	}
+
+	found := false
+
+	for _, event := range parsed {
+		report := eventlog.Analyze(repoInstance, event)
+
+		if !report.HasCodes() {
+			continue
+		}
+
+		found = true
+
+		fmt.Printf("# Event %d from %s (%s, %s)\n\n", event.EventID, event.Provider, event.LevelName(), event.TimeCreated.Format(time.RFC3339))
+
+		if bugCheck := report.BugCheck; bugCheck != nil {
+			name := bugCheck.Name
+
+			if name == "" {
+				name = "Unknown bug check"
+			}
+
+			fmt.Printf("Bug check: %s (0x%08X)\n", name, bugCheck.Code)
+			printBugCheckParameters(bugCheck.Parameters)
+		}
+
+		for _, finding := range report.Findings {
+			fmt.Printf("%s: %s (0x%08X)\n", finding.Field, finding.Text, finding.Code)
+
+			if len(finding.Matches) == 0 {
+				fmt.Println("  not a known error code")
+			}
+
+			for _, match := range finding.Matches {
+				fmt.Printf("  `%s` (%s)\n", match.Name, match.Kind)
+
+				for _, line := range strings.Split(match.Description, "\n") {
+					fmt.Printf("  > %s\n", strings.TrimSpace(line))
+				}
+			}
+		}
+
+		fmt.Println()
+	}
+
+	if parseErr != nil {
+		log.Printf("only the first %d events could be read: %v\n", len(parsed), parseErr)
+	}
+
+	if !found {
+		log.Fatalf("could not find any error codes in the events in %s\n", *events)
+	}
+}
+
+func analyzeLog(repoInstance repo.Repo) {
+	input, err := readInput(*logFile)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	analysis, err := updatelog.Analyze(repoInstance, bytes.NewReader(input))
+
+	if err != nil {
+		log.Fatalf("could not read %s: %v\n", *logFile, err)
+	}
+
+	if len(analysis.Failures) == 0 {
+		log.Fatalf("could not find any failure codes in %s\n", *logFile)
+	}
+
+	first := analysis.Failures[0].First
+
+	fmt.Printf("# Failures in %s\n\n", analysis.Format)
+	fmt.Printf("Covers %s to %s (%d lines)\n", analysis.Start.Format(time.DateTime), analysis.End.Format(time.DateTime), analysis.Lines)
+	fmt.Printf("First failure at %s, line %d:\n> %s\n\n", first.Time.Format(time.DateTime), first.Line, first.Text)
+
+	for _, failure := range analysis.Failures {
+		fmt.Printf("0x%08X: seen %d times, first at %s (line %d), last at %s (line %d)\n", failure.Code, failure.Count,
+			failure.First.Time.Format(time.DateTime), failure.First.Line, failure.Last.Time.Format(time.DateTime), failure.Last.Line)
+
+		if len(failure.Matches) == 0 && failure.Name != "" {
+			fmt.Printf("  not a known error code, the log calls it %s\n", failure.Name)
+		} else if len(failure.Matches) == 0 {
+			fmt.Println("  not a known error code")
+		}
+
+		for _, match := range failure.Matches {
+			fmt.Printf("  `%s` (%s)\n", match.Name, match.Kind)
+
+			for _, line := range strings.Split(match.Description, "\n") {
+				fmt.Printf("  > %s\n", strings.TrimSpace(line))
+			}
+		}
+	}
+}
+
+// readInput reads a file, or standard input if name is -.
+func readInput(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(name)
+}
+
+func findExtracted(repoInstance repo.Repo, match util.CodeMatch, kinds []repo.Kind) []repo.ErrorInfo {
+	matches := []repo.ErrorInfo{}
+
+	for _, kind := range kinds {
+		if match.IsName() {
+			for _, result := range repoInstance.FindByName(match.Text, kind) {
+				if strings.EqualFold(result.Name, match.Text) {
+					matches = append(matches, result.ErrorInfo)
+				}
+			}
+		} else {
+			for _, code := range match.Codes {
+				matches = append(matches, repoInstance.Find(kind, code)...)
+			}
+		}
+	}
+
+	return matches
+}
+
+// interpretValue reads a code or evaluates a macro, resolving the names
+// used as its arguments through the repository.
+func interpretValue(repoInstance repo.Repo, value string) ([]util.Interpretation, error) {
+	if !util.IsMacro(value) {
+		return util.InterpretCode(value)
+	}
+
+	code, err := util.EvaluateMacro(value, func(name string) (uint32, bool) {
+		return resolveName(repoInstance, name)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []util.Interpretation{{Code: code, Format: "macro"}}, nil
+}
+
+func resolveName(repoInstance repo.Repo, name string) (uint32, bool) {
+	for _, match := range repoInstance.FindByName(name) {
+		if strings.EqualFold(match.Name, name) {
+			return match.Code, true
+		}
+	}
+
+	if facility, ok := repoInstance.FindFacility(name); ok {
+		return uint32(facility), true
+	}
+
+	return 0, false
+}
+
 func formatResults(errors []repo.ErrorInfo) string {
 	var result []byte
 
@@ -76,6 +457,10 @@ func formatResults(errors []repo.ErrorInfo) string {
 		for _, line := range strings.Split(item.Description, "\n") {
 			result = fmt.Appendf(result, "> %s\n", strings.TrimSpace(line))
 		}
+
+		if availability := item.Availability(); availability != "" {
+			result = fmt.Appendf(result, "%s\n", availability)
+		}
 	}
 
 	return string(result)
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+var (
+	messageIdRegex   = regexp.MustCompile(`^//\s*MessageId:\s*(\w+)`)
+	messageTextRegex = regexp.MustCompile(`^//\s*MessageText:`)
+	// aliasRegex matches definitions of one name as another or as a macro
+	// applied to others, e.g.
+	//
+	//	#define SEC_E_NOT_SUPPORTED              SEC_E_UNSUPPORTED_FUNCTION
+	//	#define E_NOT_SET                        HRESULT_FROM_WIN32(ERROR_NOT_FOUND)
+	aliasRegex = regexp.MustCompile(`(?m)^\s*#define\s+(\w+)\s+([A-Za-z_]\w*(?:\s*\([\w\s,()]*\))?)\s*(?://.*)?$`)
+	// identifierRegex matches the names an alias refers to
+	identifierRegex = regexp.MustCompile(`[A-Za-z_]\w*`)
+)
+
+// headerFormat says how a kind of code is defined in the SDK headers.
+type headerFormat struct {
+	regex *regexp.Regexp
+	// macros are the macros aliases may use to define codes of this kind
+	// in terms of codes of other kinds
+	macros []string
+}
+
+var headerFormats = map[string]headerFormat{
+	"ntstatus":   {regex: ntStatusRegex, macros: []string{"NTSTATUS_FROM_WIN32"}},
+	"hresult":    {regex: hResultRegex, macros: []string{"HRESULT_FROM_WIN32", "__HRESULT_FROM_WIN32", "HRESULT_FROM_NT", "MAKE_HRESULT"}},
+	"win32error": {regex: win32ErrorRegex},
+}
+
+// parseHeader collects the codes of the given format a header defines, in the
+// order it defines them, with the descriptions from the MessageText comments
+// above them. Aliases get the description of the code they stand for unless
+// they have their own.
+func parseHeader(header string, format headerFormat) ([]errorInfo, error) {
+	messages := parseMessageTexts(header)
+	codes := map[string]uint32{}
+	positions := map[string]int{}
+	errors := []errorInfo{}
+
+	for _, match := range format.regex.FindAllStringSubmatchIndex(header, -1) {
+		name := header[match[2]:match[3]]
+		code, err := strconv.ParseUint(header[match[4]:match[5]], 0, 32)
+
+		if err != nil {
+			return nil, err
+		}
+
+		codes[name] = uint32(code)
+		positions[name] = match[0]
+		errors = append(errors, errorInfo{
+			Name:        name,
+			Code:        uint32Hex(code),
+			Description: messages[name],
+		})
+	}
+
+	// macros may take codes of any kind the header defines
+	values := map[string]uint32{}
+
+	for _, other := range headerFormats {
+		for _, match := range other.regex.FindAllStringSubmatch(header, -1) {
+			if code, err := strconv.ParseUint(match[2], 0, 32); err == nil {
+				values[match[1]] = uint32(code)
+			}
+		}
+	}
+
+	resolve := func(name string) (uint32, bool) {
+		if code, ok := codes[name]; ok {
+			return code, true
+		}
+
+		code, ok := values[name]
+		return code, ok
+	}
+
+	aliases := aliasRegex.FindAllStringSubmatchIndex(header, -1)
+
+	// aliases of aliases resolve once the alias they refer to has
+	for resolved := true; resolved; {
+		resolved = false
+
+		for _, match := range aliases {
+			name, expression := header[match[2]:match[3]], header[match[4]:match[5]]
+
+			if _, ok := codes[name]; ok {
+				continue
+			}
+
+			code, ok := resolveAlias(expression, format, codes, resolve)
+
+			if !ok {
+				continue
+			}
+
+			description := messages[name]
+
+			for _, reference := range identifierRegex.FindAllString(expression, -1) {
+				if description != "" {
+					break
+				}
+
+				description = descriptionOf(errors, reference, messages)
+			}
+
+			codes[name] = code
+			positions[name] = match[0]
+			errors = append(errors, errorInfo{
+				Name:        name,
+				Code:        uint32Hex(code),
+				Description: description,
+			})
+			resolved = true
+		}
+	}
+
+	slices.SortStableFunc(errors, func(a, b errorInfo) int {
+		return positions[a.Name] - positions[b.Name]
+	})
+
+	return errors, nil
+}
+
+// resolveAlias computes the code an alias stands for. Plain aliases must
+// name a code of the format, macros must be ones that define codes of it.
+func resolveAlias(expression string, format headerFormat, codes map[string]uint32, resolve util.NameResolver) (uint32, bool) {
+	if !util.IsMacro(expression) {
+		code, ok := codes[expression]
+		return code, ok
+	}
+
+	name, _, _ := strings.Cut(expression, "(")
+
+	if !slices.Contains(format.macros, strings.TrimSpace(name)) {
+		return 0, false
+	}
+
+	code, err := util.EvaluateMacro(expression, resolve)
+
+	return code, err == nil
+}
+
+func descriptionOf(errors []errorInfo, name string, messages map[string]string) string {
+	for _, entry := range errors {
+		if entry.Name == name && entry.Description != "" {
+			return entry.Description
+		}
+	}
+
+	return messages[name]
+}
+
+// parseMessageTexts collects the message texts of the comment blocks the
+// message compiler writes above each definition, keyed by symbolic name:
+//
+//	//
+//	// MessageId: WU_E_NO_SERVICE
+//	//
+//	// MessageText:
+//	//
+//	// Windows Update Agent was unable to provide the service.
+//	//
+//
+// Texts spanning several lines keep their line breaks as CRLF, the way
+// FormatMessage returns them.
+func parseMessageTexts(header string) map[string]string {
+	messages := map[string]string{}
+
+	var (
+		name   string
+		text   []string
+		inText bool
+	)
+
+	flush := func() {
+		if message := unescapeMessage(strings.Join(text, "\r\n")); name != "" && message != "" {
+			messages[name] = message
+		}
+
+		name, text, inText = "", nil, false
+	}
+
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+
+		if !strings.HasPrefix(line, "//") {
+			flush()
+			continue
+		}
+
+		if match := messageIdRegex.FindStringSubmatch(line); match != nil {
+			flush()
+			name = match[1]
+			continue
+		}
+
+		if messageTextRegex.MatchString(line) {
+			inText = name != ""
+			continue
+		}
+
+		if inText {
+			text = append(text, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+		}
+	}
+
+	flush()
+
+	return messages
+}
+
+// unescapeMessage applies the escape sequences of the message compiler the
+// way FormatMessage does when it ignores inserts. Inserts such as %1 or
+// %2!lu! and the printf-style formats of NTSTATUS texts are left as they are.
+func unescapeMessage(text string) string {
+	var result strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' || i+1 == len(text) {
+			result.WriteByte(text[i])
+			continue
+		}
+
+		switch next := text[i+1]; {
+		case next == '0' && (i+2 == len(text) || text[i+2] < '0' || text[i+2] > '9'):
+			// %0 ends the message without a line break
+			return strings.TrimSpace(result.String())
+		case next == 'n':
+			result.WriteString("\r\n")
+
+			// a hard line break at the end of a line replaces the line's own
+			if strings.HasPrefix(text[i+2:], "\r\n") {
+				i += 2
+			}
+		case next == 'r':
+			result.WriteByte('\r')
+		case next == 't':
+			result.WriteByte('\t')
+		case strings.IndexByte("%.! ", next) >= 0:
+			result.WriteByte(next)
+		default:
+			result.WriteByte('%')
+			continue
+		}
+
+		i++
+	}
+
+	return strings.TrimSpace(result.String())
+}
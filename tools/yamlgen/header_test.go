@@ -0,0 +1,133 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testHeader = `
+//
+// MessageId: ERROR_NOT_FOUND
+//
+// MessageText:
+//
+// Element not found.
+//
+#define ERROR_NOT_FOUND                  1168L
+
+//
+// MessageId: E_FILE_IN_USE
+//
+// MessageText:
+//
+// The file %1 is in use by
+// another process.
+//
+#define E_FILE_IN_USE                    _HRESULT_TYPEDEF_(0x80040001L)
+
+//
+// MessageId: E_QUOTA
+//
+// MessageText:
+//
+// The quota is at 100%%.%n
+// Free some space.
+//
+#define E_QUOTA                          _HRESULT_TYPEDEF_(0x80040002L)
+
+//
+// MessageId: E_UNDOCUMENTED
+//
+#define E_UNDOCUMENTED                   _HRESULT_TYPEDEF_(0x80040003L)
+
+#define E_FILE_LOCKED                    E_FILE_IN_USE
+#define E_FILE_BUSY                      E_FILE_LOCKED
+
+//
+// MessageId: E_DISK_FULL
+//
+// MessageText:
+//
+// The disk is full.
+//
+#define E_DISK_FULL                      E_QUOTA
+
+#define E_NOT_SET                        HRESULT_FROM_WIN32(ERROR_NOT_FOUND)
+#define STATUS_NOT_SET                   NTSTATUS_FROM_WIN32(ERROR_NOT_FOUND)
+`
+
+func TestParseHeader(t *testing.T) {
+	// STATUS_NOT_SET is left out, NTSTATUS_FROM_WIN32 does not define HRESULTs
+	expected := []errorInfo{
+		{Code: 0x80040001, Name: "E_FILE_IN_USE", Description: "The file %1 is in use by\r\nanother process."},
+		{Code: 0x80040002, Name: "E_QUOTA", Description: "The quota is at 100%.\r\nFree some space."},
+		{Code: 0x80040003, Name: "E_UNDOCUMENTED", Description: ""},
+		{Code: 0x80040001, Name: "E_FILE_LOCKED", Description: "The file %1 is in use by\r\nanother process."},
+		{Code: 0x80040001, Name: "E_FILE_BUSY", Description: "The file %1 is in use by\r\nanother process."},
+		{Code: 0x80040002, Name: "E_DISK_FULL", Description: "The disk is full."},
+		{Code: 0x80070490, Name: "E_NOT_SET", Description: "Element not found."},
+	}
+
+	result, err := parseHeader(testHeader, headerFormats["hresult"])
+
+	if err != nil {
+		t.Fatalf("parseHeader() unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parseHeader() = %+v, expected %+v", result, expected)
+	}
+}
+
+func TestParseMessageTexts(t *testing.T) {
+	messages := parseMessageTexts(testHeader)
+
+	tests := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{name: "ERROR_NOT_FOUND", expected: "Element not found.", ok: true},
+		{name: "E_FILE_IN_USE", expected: "The file %1 is in use by\r\nanother process.", ok: true},
+		{name: "E_DISK_FULL", expected: "The disk is full.", ok: true},
+		{name: "E_UNDOCUMENTED", expected: "", ok: false},
+		{name: "E_FILE_LOCKED", expected: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if message, ok := messages[tt.name]; message != tt.expected || ok != tt.ok {
+				t.Errorf("messages[%s] = %q, %v, expected %q, %v", tt.name, message, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestUnescapeMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "plain", text: "Access is denied.", expected: "Access is denied."},
+		{name: "percent", text: "100%% done.", expected: "100% done."},
+		{name: "line break", text: "First.%nSecond.", expected: "First.\r\nSecond."},
+		{name: "line break at end of line", text: "First.%n\r\nSecond.", expected: "First.\r\nSecond."},
+		{name: "tab", text: "Name:%tvalue", expected: "Name:\tvalue"},
+		{name: "escaped punctuation", text: "Stop%. Go%!", expected: "Stop. Go!"},
+		{name: "end of message", text: "Truncated.%0 Not shown.", expected: "Truncated."},
+		{name: "insert", text: "The file %1 was not found.", expected: "The file %1 was not found."},
+		{name: "insert with format", text: "Error %2!lu! occurred.", expected: "Error %2!lu! occurred."},
+		{name: "insert 10", text: "Value %10 is invalid.", expected: "Value %10 is invalid."},
+		{name: "printf format", text: "The instruction at 0x%p referenced memory at 0x%p.", expected: "The instruction at 0x%p referenced memory at 0x%p."},
+		{name: "trailing percent", text: "100%", expected: "100%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := unescapeMessage(tt.text); result != tt.expected {
+				t.Errorf("unescapeMessage(%q) = %q, expected %q", tt.text, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// sdkHeader is a copy of a header from a particular SDK build.
+type sdkHeader struct {
+	build string
+	path  string
+}
+
+// parseHistory reads the comma-separated build=header pairs of -history,
+// e.g. 10.0.17763.0=17763/winerror.h,10.0.19041.0=19041/winerror.h, and
+// returns them oldest first whatever order they were given in.
+func parseHistory(history string) ([]sdkHeader, error) {
+	headers := []sdkHeader{}
+
+	for _, pair := range strings.Split(history, ",") {
+		build, path, ok := strings.Cut(strings.TrimSpace(pair), "=")
+
+		if !ok || build == "" || path == "" {
+			return nil, fmt.Errorf("invalid SDK header %q, expected build=header", pair)
+		}
+
+		if _, err := parseBuild(build); err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, sdkHeader{build: build, path: path})
+	}
+
+	slices.SortStableFunc(headers, func(a, b sdkHeader) int {
+		return compareBuilds(a.build, b.build)
+	})
+
+	for i := 1; i < len(headers); i++ {
+		if compareBuilds(headers[i-1].build, headers[i].build) == 0 {
+			return nil, fmt.Errorf("SDK build %s is given twice", headers[i].build)
+		}
+	}
+
+	return headers, nil
+}
+
+// parseBuild reads the numbers of an SDK build such as 10.0.19041.0.
+func parseBuild(build string) ([]int, error) {
+	numbers := []int{}
+
+	for _, part := range strings.Split(build, ".") {
+		number, err := strconv.Atoi(part)
+
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("invalid SDK build %q, expected numbers separated by dots", build)
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+// compareBuilds orders SDK builds by version, so 10.0.9200.0 comes before
+// 10.0.10240.0. Missing numbers count as 0. Both builds must be valid.
+func compareBuilds(a, b string) int {
+	first, _ := parseBuild(a)
+	second, _ := parseBuild(b)
+
+	for len(first) < len(second) {
+		first = append(first, 0)
+	}
+
+	for len(second) < len(first) {
+		second = append(second, 0)
+	}
+
+	return slices.Compare(first, second)
+}
+
+// definitions are the codes a header defines, keyed by symbolic name.
+type definitions struct {
+	names    []string
+	codes    map[string]uint32
+	messages map[string]string
+}
+
+func (defined definitions) defines(name string) bool {
+	_, ok := defined.codes[name]
+	return ok
+}
+
+func readDefinitions(path string, format headerFormat) (definitions, error) {
+	header, err := os.ReadFile(path)
+
+	if err != nil {
+		return definitions{}, err
+	}
+
+	errors, err := parseHeader(string(header), format)
+
+	if err != nil {
+		return definitions{}, err
+	}
+
+	defined := definitions{
+		codes:    map[string]uint32{},
+		messages: map[string]string{},
+	}
+
+	for _, entry := range errors {
+		if !defined.defines(entry.Name) {
+			defined.names = append(defined.names, entry.Name)
+		}
+
+		defined.codes[entry.Name] = uint32(entry.Code)
+		defined.messages[entry.Name] = entry.Description
+	}
+
+	return defined, nil
+}
+
+// annotateHistory compares the header given with -h, which is from the SDK
+// build given with -sdk, to the older headers given with -history, in the
+// order of their builds. Entries
+// get the build they were introduced in, unless they are defined by the
+// oldest header already, and the codes the newest header no longer defines
+// are added with the build they were removed in. Their descriptions come
+// from the MessageText comments of the newest header that has one for them.
+func annotateHistory(errors []errorInfo, format headerFormat) []errorInfo {
+	if *historyList == "" {
+		return errors
+	}
+
+	if *sdkBuild == "" {
+		fmt.Fprintln(os.Stderr, "-history needs the SDK build of the -h header in -sdk")
+		os.Exit(1)
+	}
+
+	if _, err := parseBuild(*sdkBuild); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	headers, err := parseHistory(*historyList)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if newest := headers[len(headers)-1].build; compareBuilds(newest, *sdkBuild) >= 0 {
+		fmt.Fprintf(os.Stderr, "-history headers must be older than the -sdk build %s, %s is not\n", *sdkBuild, newest)
+		os.Exit(1)
+	}
+
+	builds := []string{}
+	versions := []definitions{}
+
+	for _, header := range headers {
+		defined, err := readDefinitions(header.path, format)
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		builds = append(builds, header.build)
+		versions = append(versions, defined)
+	}
+
+	latest := definitions{codes: map[string]uint32{}}
+
+	for _, entry := range errors {
+		latest.codes[entry.Name] = uint32(entry.Code)
+	}
+
+	builds = append(builds, *sdkBuild)
+	versions = append(versions, latest)
+
+	// introduced returns the build from which on the name has been defined
+	// up to the version at index last
+	introduced := func(name string, last int) string {
+		first := last
+
+		for first > 0 && versions[first-1].defines(name) {
+			first--
+		}
+
+		if first == 0 {
+			return ""
+		}
+
+		return builds[first]
+	}
+
+	for i := range errors {
+		errors[i].Introduced = introduced(errors[i].Name, len(versions)-1)
+	}
+
+	seen := map[string]bool{}
+
+	for _, version := range versions[:len(versions)-1] {
+		for _, name := range version.names {
+			if latest.defines(name) || seen[name] {
+				continue
+			}
+
+			seen[name] = true
+
+			last := len(versions) - 2
+
+			for !versions[last].defines(name) {
+				last--
+			}
+
+			description := ""
+
+			for i := last; i >= 0 && description == ""; i-- {
+				description = versions[i].messages[name]
+			}
+
+			errors = append(errors, errorInfo{
+				Code:        uint32Hex(versions[last].codes[name]),
+				Name:        name,
+				Description: description,
+				Introduced:  introduced(name, last),
+				Removed:     builds[last+1],
+			})
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  string
+		expected []sdkHeader
+		wantErr  bool
+	}{
+		{
+			name:     "one header",
+			history:  "10.0.17763.0=17763/winerror.h",
+			expected: []sdkHeader{{build: "10.0.17763.0", path: "17763/winerror.h"}},
+		},
+		{
+			name:    "several headers",
+			history: "10.0.17763.0=17763/winerror.h, 10.0.19041.0=19041/winerror.h",
+			expected: []sdkHeader{
+				{build: "10.0.17763.0", path: "17763/winerror.h"},
+				{build: "10.0.19041.0", path: "19041/winerror.h"},
+			},
+		},
+		{
+			name:    "out of order",
+			history: "10.0.19041.0=19041/winerror.h,10.0.10240.0=10240/winerror.h,10.0.9200.0=9200/winerror.h",
+			expected: []sdkHeader{
+				{build: "10.0.9200.0", path: "9200/winerror.h"},
+				{build: "10.0.10240.0", path: "10240/winerror.h"},
+				{build: "10.0.19041.0", path: "19041/winerror.h"},
+			},
+		},
+		{name: "missing build", history: "=17763/winerror.h", wantErr: true},
+		{name: "invalid build", history: "latest=17763/winerror.h", wantErr: true},
+		{name: "build given twice", history: "10.0.17763.0=a/winerror.h,10.0.17763=b/winerror.h", wantErr: true},
+		{name: "missing header", history: "10.0.17763.0=", wantErr: true},
+		{name: "no separator", history: "17763/winerror.h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseHistory(tt.history)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHistory(%q) error = %v, wantErr %v", tt.history, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseHistory(%q) = %+v, expected %+v", tt.history, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompareBuilds(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "10.0.19041.0", b: "10.0.19041.0", expected: 0},
+		{a: "10.0.17763.0", b: "10.0.19041.0", expected: -1},
+		{a: "10.0.22621.0", b: "10.0.19041.0", expected: 1},
+		// numbers compare as numbers, not as text
+		{a: "10.0.9200.0", b: "10.0.10240.0", expected: -1},
+		{a: "10.0.22621.755", b: "10.0.22621.0", expected: 1},
+		{a: "10.0.19041", b: "10.0.19041.0", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if result := compareBuilds(tt.a, tt.b); result != tt.expected {
+				t.Errorf("compareBuilds(%q, %q) = %d, expected %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+// writeHeaders writes the headers of older SDK builds to a temporary
+// directory and returns them as -history expects them.
+func writeHeaders(t *testing.T, headers ...[2]string) string {
+	dir := t.TempDir()
+	history := ""
+
+	for i, header := range headers {
+		path := filepath.Join(dir, header[0]+".h")
+
+		if err := os.WriteFile(path, []byte(header[1]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if i > 0 {
+			history += ","
+		}
+
+		history += header[0] + "=" + path
+	}
+
+	return history
+}
+
+func TestAnnotateHistory(t *testing.T) {
+	oldHistory, oldBuild := *historyList, *sdkBuild
+	t.Cleanup(func() {
+		*historyList, *sdkBuild = oldHistory, oldBuild
+	})
+
+	// E_DROPPED is added in 17763 and removed in 22621, E_LEGACY is defined
+	// by the oldest header and removed in 19041, E_NEW is added in 22621.
+	// The headers are not given oldest first.
+	*historyList = writeHeaders(t,
+		[2]string{"10.0.19041.0", `
+#define E_KEPT                           _HRESULT_TYPEDEF_(0x80040002L)
+#define E_DROPPED                        _HRESULT_TYPEDEF_(0x80040003L)
+`},
+		[2]string{"10.0.10240.0", `
+//
+// MessageId: E_LEGACY
+//
+// MessageText:
+//
+// Legacy failure.
+//
+#define E_LEGACY                         _HRESULT_TYPEDEF_(0x80040001L)
+#define E_KEPT                           _HRESULT_TYPEDEF_(0x80040002L)
+`},
+		[2]string{"10.0.17763.0", `
+#define E_LEGACY                         _HRESULT_TYPEDEF_(0x80040001L)
+#define E_KEPT                           _HRESULT_TYPEDEF_(0x80040002L)
+//
+// MessageId: E_DROPPED
+//
+// MessageText:
+//
+// Dropped failure.
+//
+#define E_DROPPED                        _HRESULT_TYPEDEF_(0x80040003L)
+`},
+	)
+	*sdkBuild = "10.0.22621.0"
+
+	errors := []errorInfo{
+		{Code: 0x80040002, Name: "E_KEPT", Description: "Kept failure."},
+		{Code: 0x80040004, Name: "E_NEW", Description: "New failure."},
+	}
+
+	expected := []errorInfo{
+		{Code: 0x80040002, Name: "E_KEPT", Description: "Kept failure."},
+		{Code: 0x80040004, Name: "E_NEW", Description: "New failure.", Introduced: "10.0.22621.0"},
+		{Code: 0x80040001, Name: "E_LEGACY", Description: "Legacy failure.", Removed: "10.0.19041.0"},
+		{Code: 0x80040003, Name: "E_DROPPED", Description: "Dropped failure.", Introduced: "10.0.17763.0", Removed: "10.0.22621.0"},
+	}
+
+	if result := annotateHistory(errors, headerFormats["hresult"]); !reflect.DeepEqual(result, expected) {
+		t.Errorf("annotateHistory() = %+v, expected %+v", result, expected)
+	}
+}
+
+func TestAnnotateHistory_NoHistory(t *testing.T) {
+	oldHistory := *historyList
+	t.Cleanup(func() {
+		*historyList = oldHistory
+	})
+
+	*historyList = ""
+
+	errors := []errorInfo{{Code: 0x80040002, Name: "E_KEPT", Description: "Kept failure."}}
+
+	if result := annotateHistory(errors, headerFormats["hresult"]); !reflect.DeepEqual(result, errors) {
+		t.Errorf("annotateHistory() = %+v, expected %+v", result, errors)
+	}
+}
@@ -23,6 +23,10 @@ var (
 	messagesPath = flag.String("mt", "", "")
 	outputPath   = flag.String("o", "-", "")
 	mode         = flag.String("m", "", "")
+	win32Path    = flag.String("w", "", "")
+	sdkBuild     = flag.String("sdk", "", "")
+	historyList  = flag.String("history", "", "")
+	customerBit  = flag.Bool("c", false, "")
 )
 
 var codeFormat string = "0x%08X"
@@ -40,33 +44,85 @@ type errorInfo struct {
 	Code        uint32Hex
 	Name        string
 	Description string
+	Introduced  string `yaml:",omitempty"`
+	Removed     string `yaml:",omitempty"`
+	// Translations holds the texts of messages in languages other than
+	// the one of the description
+	Translations map[string]string `yaml:",omitempty"`
 }
 
 func main() {
 	flag.Parse()
 
-	if *headerPath == "" || *messagesPath == "" {
+	if *mode == "ntstatusmap" {
+		generateNTStatusMap()
+		return
+	} else if *mode == "mc" {
+		generateMessageCatalog()
+		return
+	}
+
+	if *headerPath == "" {
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	var (
-		err      error
-		header   []byte
-		messages []byte
-		// outputFile       *os.File
-	)
+	var format headerFormat
 
-	if header, err = os.ReadFile(*headerPath); err != nil {
+	switch *mode {
+	case "ntstatus":
+		format = headerFormats["ntstatus"]
+	case "hresult", "wuerror":
+		format = headerFormats["hresult"]
+	case "win32error":
+		codeFormat = "%d"
+		format = headerFormats["win32error"]
+	default:
+		fmt.Fprintf(os.Stderr, "invalid mode %s\n", *mode)
+		os.Exit(1)
+	}
+
+	header, err := os.ReadFile(*headerPath)
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	if messages, err = os.ReadFile(*messagesPath); err != nil {
+	errors, err := parseHeader(string(header), format)
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
+	// a message table dumped from the system (-mt) wins over the texts in
+	// the header, which may be older than the system's
+	if *messagesPath != "" {
+		messageMap, err := readMessageTable(*messagesPath)
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		for i := range errors {
+			if message, ok := messageMap[uint32(errors[i].Code)]; ok {
+				errors[i].Description = message
+			}
+		}
+	}
+
+	writeYAML(annotateHistory(errors, format))
+}
+
+func readMessageTable(path string) (map[uint32]string, error) {
+	messages, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
 	messageMap := map[uint32]string{}
 	messageMatches := messageRegex.FindAllStringSubmatch(string(messages), -1)
 
@@ -74,8 +130,7 @@ func main() {
 		code, err := strconv.ParseUint(match[1], 0, 32)
 
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			return nil, err
 		}
 
 		message := match[2]
@@ -87,41 +142,11 @@ func main() {
 		messageMap[uint32(code)] = message
 	}
 
-	var headerRegex *regexp.Regexp
-
-	switch *mode {
-	case "ntstatus":
-		headerRegex = ntStatusRegex
-	case "hresult":
-		headerRegex = hResultRegex
-	case "win32error":
-		codeFormat = "%d"
-		headerRegex = win32ErrorRegex
-	default:
-		fmt.Fprintf(os.Stderr, "invalid mode %s\n", *mode)
-		os.Exit(1)
-	}
-
-	errors := []errorInfo{}
-
-	headerMatches := headerRegex.FindAllStringSubmatch(string(header), -1)
-
-	for _, match := range headerMatches {
-		code, err := strconv.ParseUint(match[2], 0, 32)
-
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
-
-		errors = append(errors, errorInfo{
-			Name:        match[1],
-			Code:        uint32Hex(code),
-			Description: messageMap[uint32(code)],
-		})
-	}
+	return messageMap, nil
+}
 
-	yaml, err := yaml.Marshal(errors)
+func writeYAML(value interface{}) {
+	yaml, err := yaml.Marshal(value)
 
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
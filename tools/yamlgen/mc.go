@@ -0,0 +1,330 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// mcKeywordRegex matches the keyword lines of message files, e.g.
+	//
+	//	MessageId=0x1
+	//	SeverityNames=(Success=0x0:STATUS_SEVERITY_SUCCESS
+	mcKeywordRegex = regexp.MustCompile(`^\s*(\w+)\s*=\s*(.*?)\s*$`)
+	// mcNameRegex matches the entries of the SeverityNames, FacilityNames and
+	// LanguageNames blocks, e.g. Error=0x3:STATUS_SEVERITY_ERROR
+	mcNameRegex = regexp.MustCompile(`(\w+)\s*=\s*(0[xX][0-9A-Fa-f]+|\d+)(?:\s*:\s*(\w+))?`)
+)
+
+// mcName is a value a message file gives a name to, along with the
+// symbolic name the message compiler defines for it, if any.
+type mcName struct {
+	name     string
+	value    uint32
+	symbolic string
+}
+
+type messageCatalog struct {
+	Facilities map[uint16]string `yaml:",omitempty"`
+	Languages  []string
+	Codes      []errorInfo
+}
+
+// messageFile holds the state of the message compiler while it reads a
+// message file.
+type messageFile struct {
+	severities map[string]mcName
+	facilities map[string]mcName
+	languages  map[string]mcName
+
+	severity string
+	facility string
+	// lastIds are the last message ids of each facility
+	lastIds map[string]uint32
+
+	catalog messageCatalog
+}
+
+// generateMessageCatalog compiles a message compiler source file (-h) into
+// a catalog with the codes the message compiler would define for it.
+func generateMessageCatalog() {
+	if *headerPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	source, err := os.ReadFile(*headerPath)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	catalog, err := parseMessageFile(string(source))
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s:%v\n", *headerPath, err)
+		os.Exit(1)
+	}
+
+	writeYAML(catalog)
+}
+
+// parseMessageFile reads the header section and the message definitions of
+// a message file. The names of severities, facilities and languages start
+// out with the message compiler's defaults, and severities and facilities
+// carry over from one message to the next as they do in mc.
+func parseMessageFile(source string) (messageCatalog, error) {
+	file := messageFile{
+		severities: map[string]mcName{
+			"success":       {name: "Success", value: 0x0},
+			"informational": {name: "Informational", value: 0x1},
+			"warning":       {name: "Warning", value: 0x2},
+			"error":         {name: "Error", value: 0x3},
+		},
+		facilities: map[string]mcName{
+			"system":      {name: "System", value: 0x0FF},
+			"application": {name: "Application", value: 0xFFF},
+		},
+		languages: map[string]mcName{
+			"english": {name: "English", value: 0x409, symbolic: "MSG00409"},
+		},
+		severity: "success",
+		facility: "application",
+		lastIds:  map[string]uint32{},
+		catalog: messageCatalog{
+			Facilities: map[uint16]string{},
+		},
+	}
+
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		// comments and blank lines
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		match := mcKeywordRegex.FindStringSubmatch(line)
+
+		if match == nil {
+			return messageCatalog{}, fmt.Errorf("%d: expected a keyword, got %q", i+1, line)
+		}
+
+		keyword, value := strings.ToLower(match[1]), match[2]
+
+		switch keyword {
+		case "severitynames", "facilitynames", "languagenames":
+			// the names may span several lines up to the closing parenthesis
+			for !strings.Contains(value, ")") && i+1 < len(lines) {
+				i++
+				value += " " + strings.TrimSpace(lines[i])
+			}
+
+			names := map[string]map[string]mcName{
+				"severitynames": file.severities,
+				"facilitynames": file.facilities,
+				"languagenames": file.languages,
+			}[keyword]
+
+			for _, name := range mcNameRegex.FindAllStringSubmatch(value, -1) {
+				number, err := strconv.ParseUint(name[2], 0, 32)
+
+				if err != nil {
+					return messageCatalog{}, fmt.Errorf("%d: %w", i+1, err)
+				}
+
+				names[strings.ToLower(name[1])] = mcName{name: name[1], value: uint32(number), symbolic: name[3]}
+			}
+		case "messageid":
+			next, err := file.parseMessage(lines, i, value)
+
+			if err != nil {
+				return messageCatalog{}, err
+			}
+
+			i = next - 1
+		case "messageidtypedef", "messageidtypedefmacro", "outputbase":
+			// only affect the generated header
+		default:
+			return messageCatalog{}, fmt.Errorf("%d: unknown keyword %s", i+1, match[1])
+		}
+	}
+
+	return file.catalog, nil
+}
+
+// parseMessage reads the message definition starting at the MessageId line
+// start with the given value and returns the index of the line after it.
+func (file *messageFile) parseMessage(lines []string, start int, id string) (int, error) {
+	var (
+		name         string
+		severity     = file.severity
+		facility     = file.facility
+		translations = map[string]string{}
+		languages    = []string{}
+	)
+
+	i := start + 1
+
+	// the rest of the message header
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		match := mcKeywordRegex.FindStringSubmatch(line)
+
+		if match == nil {
+			return 0, fmt.Errorf("%d: expected a keyword, got %q", i+1, line)
+		}
+
+		keyword := strings.ToLower(match[1])
+
+		if keyword == "language" {
+			break
+		}
+
+		switch keyword {
+		case "severity":
+			severity = strings.ToLower(match[2])
+		case "facility":
+			facility = strings.ToLower(match[2])
+		case "symbolicname":
+			name = match[2]
+		default:
+			return 0, fmt.Errorf("%d: unknown message keyword %s", i+1, match[1])
+		}
+	}
+
+	// the texts, each ending with a line holding a single period
+	for i < len(lines) {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" || strings.HasPrefix(line, ";") {
+			i++
+			continue
+		}
+
+		match := mcKeywordRegex.FindStringSubmatch(line)
+
+		if match == nil || strings.ToLower(match[1]) != "language" {
+			break
+		}
+
+		language, ok := file.languages[strings.ToLower(match[2])]
+
+		if !ok {
+			return 0, fmt.Errorf("%d: unknown language %s", i+1, match[2])
+		}
+
+		text := []string{}
+
+		for i++; i < len(lines) && strings.TrimRight(lines[i], " \t\r") != "."; i++ {
+			text = append(text, strings.TrimRight(lines[i], " \t\r"))
+		}
+
+		if i == len(lines) {
+			return 0, fmt.Errorf("%d: the text of message %s is not ended with a period", start+1, id)
+		}
+
+		i++
+
+		languages = append(languages, language.name)
+		translations[language.name] = unescapeMessage(strings.Join(text, "\r\n"))
+	}
+
+	severityValue, ok := file.severities[severity]
+
+	if !ok {
+		return 0, fmt.Errorf("%d: unknown severity %s", start+1, severity)
+	}
+
+	facilityValue, ok := file.facilities[facility]
+
+	if !ok {
+		return 0, fmt.Errorf("%d: unknown facility %s", start+1, facility)
+	}
+
+	var messageId uint32
+
+	switch {
+	case id == "":
+		messageId = file.lastIds[facility] + 1
+	case strings.HasPrefix(id, "+"):
+		increment, err := strconv.ParseUint(strings.TrimSpace(id[1:]), 0, 16)
+
+		if err != nil {
+			return 0, fmt.Errorf("%d: %w", start+1, err)
+		}
+
+		messageId = file.lastIds[facility] + uint32(increment)
+	default:
+		number, err := strconv.ParseUint(id, 0, 16)
+
+		if err != nil {
+			return 0, fmt.Errorf("%d: %w", start+1, err)
+		}
+
+		messageId = uint32(number)
+	}
+
+	file.severity, file.facility = severity, facility
+	file.lastIds[facility] = messageId
+
+	code := severityValue.value<<30 | (facilityValue.value&0xFFF)<<16 | messageId&0xFFFF
+
+	if *customerBit {
+		code |= 1 << 29
+	}
+
+	if name == "" {
+		fmt.Fprintf(os.Stderr, "skipping message 0x%08X without a SymbolicName\n", code)
+		return i, nil
+	}
+
+	facilityName := facilityValue.symbolic
+
+	if facilityName == "" {
+		facilityName = facilityValue.name
+	}
+
+	file.catalog.Facilities[uint16(facilityValue.value)] = facilityName
+
+	entry := errorInfo{
+		Code: uint32Hex(code),
+		Name: name,
+	}
+
+	for i, language := range languages {
+		if i == 0 {
+			entry.Description = translations[language]
+			continue
+		}
+
+		if entry.Translations == nil {
+			entry.Translations = map[string]string{}
+		}
+
+		entry.Translations[language] = translations[language]
+	}
+
+	for _, language := range languages {
+		if !slices.Contains(file.catalog.Languages, language) {
+			file.catalog.Languages = append(file.catalog.Languages, language)
+		}
+	}
+
+	file.catalog.Codes = append(file.catalog.Codes, entry)
+
+	return i, nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMessageFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected messageCatalog
+	}{
+		{
+			name: "defaults",
+			source: `MessageId=0x1
+SymbolicName=MSG_STARTED
+Language=English
+The service has started.
+.
+`,
+			expected: messageCatalog{
+				Facilities: map[uint16]string{0xFFF: "Application"},
+				Languages:  []string{"English"},
+				Codes: []errorInfo{
+					{Code: 0x0FFF0001, Name: "MSG_STARTED", Description: "The service has started."},
+				},
+			},
+		},
+		{
+			name: "implicit message ids",
+			source: `MessageId=0x10
+SymbolicName=MSG_FIRST
+Language=English
+First.
+.
+
+MessageId=
+SymbolicName=MSG_SECOND
+Language=English
+Second.
+.
+
+MessageId=+0x4
+SymbolicName=MSG_THIRD
+Language=English
+Third.
+.
+`,
+			expected: messageCatalog{
+				Facilities: map[uint16]string{0xFFF: "Application"},
+				Languages:  []string{"English"},
+				Codes: []errorInfo{
+					{Code: 0x0FFF0010, Name: "MSG_FIRST", Description: "First."},
+					{Code: 0x0FFF0011, Name: "MSG_SECOND", Description: "Second."},
+					{Code: 0x0FFF0015, Name: "MSG_THIRD", Description: "Third."},
+				},
+			},
+		},
+		{
+			name: "multi-line text",
+			source: `MessageId=0x1
+SymbolicName=MSG_MULTILINE
+Language=English
+The first line ends with a period.
+. is only the end of the text on a line of its own
+100%% done.%n
+.
+`,
+			expected: messageCatalog{
+				Facilities: map[uint16]string{0xFFF: "Application"},
+				Languages:  []string{"English"},
+				Codes: []errorInfo{
+					{Code: 0x0FFF0001, Name: "MSG_MULTILINE", Description: "The first line ends with a period.\r\n. is only the end of the text on a line of its own\r\n100% done."},
+				},
+			},
+		},
+		{
+			name: "custom severities and facilities",
+			source: `; // custom names replace or add to the defaults
+SeverityNames=(Success=0x0:STATUS_SEVERITY_SUCCESS
+               Warning=0x2:STATUS_SEVERITY_WARNING
+               Failure=0x3:STATUS_SEVERITY_ERROR
+              )
+FacilityNames=(Io=0x4:FACILITY_IO_ERROR_CODE
+               Driver=0x7
+              )
+
+MessageId=0x1
+Severity=Failure
+Facility=Io
+SymbolicName=IO_ERR_TIMEOUT
+Language=English
+The device did not respond.
+.
+
+MessageId=0x2
+SymbolicName=IO_ERR_RESET
+Language=English
+The device was reset.
+.
+
+MessageId=0x1
+Severity=Warning
+Facility=Driver
+SymbolicName=DRV_WARN_SLOW
+Language=English
+The driver is slow.
+.
+`,
+			expected: messageCatalog{
+				Facilities: map[uint16]string{0x4: "FACILITY_IO_ERROR_CODE", 0x7: "Driver"},
+				Languages:  []string{"English"},
+				Codes: []errorInfo{
+					{Code: 0xC0040001, Name: "IO_ERR_TIMEOUT", Description: "The device did not respond."},
+					// severity and facility carry over
+					{Code: 0xC0040002, Name: "IO_ERR_RESET", Description: "The device was reset."},
+					{Code: 0x80070001, Name: "DRV_WARN_SLOW", Description: "The driver is slow."},
+				},
+			},
+		},
+		{
+			name: "languages",
+			source: `LanguageNames=(English=0x409:MSG00409 German=0x407:MSG00407)
+
+MessageId=0x1
+SymbolicName=MSG_HELLO
+Language=English
+Hello.
+.
+Language=German
+Hallo.
+.
+`,
+			expected: messageCatalog{
+				Facilities: map[uint16]string{0xFFF: "Application"},
+				Languages:  []string{"English", "German"},
+				Codes: []errorInfo{
+					{Code: 0x0FFF0001, Name: "MSG_HELLO", Description: "Hello.", Translations: map[string]string{"German": "Hallo."}},
+				},
+			},
+		},
+		{
+			name: "no symbolic name",
+			source: `MessageId=0x1
+Language=English
+Unnamed.
+.
+`,
+			expected: messageCatalog{
+				Facilities: map[uint16]string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseMessageFile(tt.source)
+
+			if err != nil {
+				t.Fatalf("parseMessageFile() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseMessageFile() = %+v, expected %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMessageFile_CustomerBit(t *testing.T) {
+	oldCustomerBit := *customerBit
+	t.Cleanup(func() {
+		*customerBit = oldCustomerBit
+	})
+
+	*customerBit = true
+
+	result, err := parseMessageFile("MessageId=0x1\nSeverity=Error\nSymbolicName=MSG_FAILED\nLanguage=English\nFailed.\n.\n")
+
+	if err != nil {
+		t.Fatalf("parseMessageFile() unexpected error: %v", err)
+	}
+
+	if len(result.Codes) != 1 || result.Codes[0].Code != 0xEFFF0001 {
+		t.Errorf("parseMessageFile() codes = %+v, expected 0xEFFF0001", result.Codes)
+	}
+}
+
+func TestParseMessageFile_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "unknown keyword", source: "Version=1\n"},
+		{name: "unknown severity", source: "MessageId=0x1\nSeverity=Fatal\nSymbolicName=MSG\nLanguage=English\nText.\n.\n"},
+		{name: "unknown facility", source: "MessageId=0x1\nFacility=Io\nSymbolicName=MSG\nLanguage=English\nText.\n.\n"},
+		{name: "unknown language", source: "MessageId=0x1\nSymbolicName=MSG\nLanguage=German\nText.\n.\n"},
+		{name: "text without period", source: "MessageId=0x1\nSymbolicName=MSG\nLanguage=English\nText.\n"},
+		{name: "invalid message id", source: "MessageId=0x10000\nSymbolicName=MSG\nLanguage=English\nText.\n.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseMessageFile(tt.source); err == nil {
+				t.Errorf("parseMessageFile(%q) expected error", tt.source)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// mappingRegex matches the entries of the RtlNtStatusToDosError tables in
+// Wine's and ReactOS's dlls/ntdll/error.c, e.g.
+//
+//	ERROR_ACCESS_DENIED,                    /* c0000022 (STATUS_ACCESS_DENIED) */
+var mappingRegex = regexp.MustCompile(`(?m)^\s*(\w+),?\s*/\*\s*([0-9A-Fa-f]{8})\b`)
+
+const (
+	// ERROR_MR_MID_NOT_FOUND is what the tables use for codes without a
+	// Win32 equivalent
+	errorMrMidNotFound = 317
+)
+
+type ntStatusMapping struct {
+	NTStatus   uint32Hex `yaml:"ntstatus"`
+	Win32Error uint32    `yaml:"win32error"`
+}
+
+// generateNTStatusMap converts the mapping tables of error.c (-h) to YAML,
+// resolving the Win32 error names through winerror.h (-w).
+func generateNTStatusMap() {
+	if *headerPath == "" || *win32Path == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	source, err := os.ReadFile(*headerPath)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	header, err := os.ReadFile(*win32Path)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	win32Errors := map[string]uint32{}
+
+	for _, match := range win32ErrorRegex.FindAllStringSubmatch(string(header), -1) {
+		code, err := strconv.ParseUint(match[2], 0, 32)
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		win32Errors[match[1]] = uint32(code)
+	}
+
+	mappings := []ntStatusMapping{}
+
+	for _, match := range mappingRegex.FindAllStringSubmatch(string(source), -1) {
+		status, err := strconv.ParseUint(match[2], 16, 32)
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		win32Error, ok := win32Errors[match[1]]
+
+		if !ok {
+			code, err := strconv.ParseUint(match[1], 0, 32)
+
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "skipping 0x%08X: unknown Win32 error %s\n", status, match[1])
+				continue
+			}
+
+			win32Error = uint32(code)
+		}
+
+		if status == 0 || win32Error == 0 || win32Error == errorMrMidNotFound {
+			continue
+		}
+
+		mappings = append(mappings, ntStatusMapping{
+			NTStatus:   uint32Hex(status),
+			Win32Error: win32Error,
+		})
+	}
+
+	slices.SortStableFunc(mappings, func(a, b ntStatusMapping) int {
+		return cmp.Compare(a.NTStatus, b.NTStatus)
+	})
+
+	writeYAML(mappings)
+}
@@ -0,0 +1,167 @@
+package updatelog
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dhrdlicka/errorbot/repo"
+	"github.com/dhrdlicka/errorbot/util"
+)
+
+// ErrNoEntries is returned for files without any line of a supported log.
+var ErrNoEntries = errors.New("no log entries found")
+
+// Analysis summarizes the failures in a log.
+type Analysis struct {
+	// Format is the format of the first entry
+	Format Format
+	Lines  int
+	// Start and End are the times of the first and the last entry
+	Start time.Time
+	End   time.Time
+	// Failures holds each failure code once, in the order they first
+	// appear in
+	Failures []Failure
+}
+
+// Failure is a failure code found in a log.
+type Failure struct {
+	Code uint32
+	// Name is the symbolic name the log gives the code, if it does
+	Name string
+	// Matches is empty if the code is not in the catalogs
+	Matches []Match
+	// Count is the number of lines the code appears on
+	Count int
+	First Occurrence
+	Last  Occurrence
+}
+
+// Match is a catalog entry a code may be.
+type Match struct {
+	repo.ErrorInfo
+	Kind repo.Kind
+}
+
+// Occurrence is a line a code appears on.
+type Occurrence struct {
+	// Line is the line number, starting at 1
+	Line int
+	// Time is the time of the entry, continuation lines have the time of
+	// the entry they continue
+	Time      time.Time
+	Component string
+	Text      string
+}
+
+// failureKinds are the catalogs failure codes in servicing logs come from,
+// Win32 errors show up wrapped in HRESULTs
+var failureKinds = []repo.Kind{repo.KindHResult, repo.KindNTStatus}
+
+// Analyze scans a log line by line for failure codes, which are looked up
+// in the catalogs. Only codes with the severity bit set are reported, the
+// logs are full of flags and success codes otherwise.
+func Analyze(r repo.Repo, input io.Reader) (Analysis, error) {
+	data, err := io.ReadAll(input)
+
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	analysis := Analysis{}
+	positions := map[uint32]int{}
+	var last Entry
+
+	for line := range strings.Lines(string(util.DecodeText(data))) {
+		analysis.Lines++
+
+		entry, ok := ParseLine(line)
+
+		if ok {
+			if analysis.Format == "" {
+				analysis.Format = entry.Format
+				analysis.Start = entry.Time
+			}
+
+			analysis.End = entry.Time
+			last = entry
+		}
+
+		occurrence := Occurrence{
+			Line:      analysis.Lines,
+			Time:      last.Time,
+			Component: last.Component,
+			Text:      strings.TrimSpace(line),
+		}
+
+		for _, failure := range findFailures(r, line) {
+			position, seen := positions[failure.Code]
+
+			if !seen {
+				position = len(analysis.Failures)
+				positions[failure.Code] = position
+
+				failure.First = occurrence
+				analysis.Failures = append(analysis.Failures, failure)
+			}
+
+			found := &analysis.Failures[position]
+			found.Count++
+			found.Last = occurrence
+
+			if found.Name == "" {
+				found.Name = failure.Name
+			}
+		}
+	}
+
+	if analysis.Format == "" {
+		return Analysis{}, ErrNoEntries
+	}
+
+	return analysis, nil
+}
+
+// findFailures finds the distinct failure codes on a line.
+func findFailures(r repo.Repo, line string) []Failure {
+	failures := []Failure{}
+	seen := map[uint32]bool{}
+	matches := util.ExtractCodes(line)
+
+	for i, match := range matches {
+		if match.IsName() || match.Codes[0]&0x80000000 == 0 {
+			continue
+		}
+
+		// lines often repeat a code, e.g. in [HRESULT = 0x800f081f - ...],
+		// and Failure.Count counts lines
+		if seen[match.Codes[0]] {
+			continue
+		}
+
+		failure := Failure{Code: match.Codes[0]}
+
+		for _, kind := range failureKinds {
+			for _, info := range r.Find(kind, failure.Code) {
+				failure.Matches = append(failure.Matches, Match{info, kind})
+			}
+		}
+
+		// CBS and DISM name the codes they log, e.g. 0x800f081f -
+		// CBS_E_SOURCE_MISSING
+		if i+1 < len(matches) && matches[i+1].IsName() {
+			between := line[match.Offset+len(match.Text) : matches[i+1].Offset]
+
+			if strings.Trim(between, " -:(") == "" {
+				failure.Name = matches[i+1].Text
+			}
+		}
+
+		seen[failure.Code] = true
+		failures = append(failures, failure)
+	}
+
+	return failures
+}
@@ -0,0 +1,134 @@
+package updatelog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhrdlicka/errorbot/repo"
+)
+
+const testCBSLog = `2024-03-05 21:14:00, Info                  CBS    TI: --- Initializing Trusted Installer ---
+2024-03-05 21:14:01, Info                  CBS    Session: 31093218_1234 initialized by client WindowsUpdateAgent. [HRESULT = 0x00000000 - S_OK]
+2024-03-05 21:14:05, Info                  CBS    Exec: Processing started.  Client: WindowsUpdateAgent, Session(Stack): 31093218_1234, Flags: 0x1
+2024-03-05 21:14:07, Error                 CBS    Failed to resolve package 'Package_for_RollupFix' [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]
+2024-03-05 21:14:07, Info                  CBS    Mark store corruption flag because of package: Package_for_RollupFix. [HRESULT = 0x80073712 - ERROR_SXS_COMPONENT_STORE_CORRUPT]
+    continued with 0x80073712 on a line without a timestamp
+2024-03-05 21:20:13, Error                 CBS    Failed to execute package: Package_for_RollupFix [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]
+2024-03-05 21:20:15, Info                  CBS    Ending TrustedInstaller finalization.
+`
+
+func createTestRepo() repo.Repo {
+	testRepo := repo.New(
+		repo.NewListCatalog("HRESULT", repo.KindHResult, nil, map[uint16]string{7: "FACILITY_WIN32"}),
+		repo.NewListCatalog("Win32 error", repo.KindWin32Error, []repo.ErrorInfo{
+			{Code: 0x3712, Name: "ERROR_SXS_COMPONENT_STORE_CORRUPT", Description: "The component store has been corrupted."},
+		}, nil),
+	)
+
+	return testRepo
+}
+
+func TestAnalyze(t *testing.T) {
+	analysis, err := Analyze(createTestRepo(), strings.NewReader(testCBSLog))
+
+	if err != nil {
+		t.Fatalf("Analyze() unexpected error: %v", err)
+	}
+
+	if analysis.Format != FormatCBS || analysis.Lines != 8 {
+		t.Errorf("Analyze() read %d lines of %s, expected 8 lines of %s", analysis.Lines, analysis.Format, FormatCBS)
+	}
+
+	if !analysis.Start.Equal(time.Date(2024, 3, 5, 21, 14, 0, 0, time.UTC)) || !analysis.End.Equal(time.Date(2024, 3, 5, 21, 20, 15, 0, time.UTC)) {
+		t.Errorf("Analyze() covers %v to %v", analysis.Start, analysis.End)
+	}
+
+	tests := []struct {
+		name      string
+		code      uint32
+		logName   string
+		matches   int
+		count     int
+		firstLine int
+		lastLine  int
+		lastTime  time.Time
+	}{
+		{
+			name:      "source missing",
+			code:      0x800F081F,
+			logName:   "CBS_E_SOURCE_MISSING",
+			matches:   0,
+			count:     2,
+			firstLine: 4,
+			lastLine:  7,
+			lastTime:  time.Date(2024, 3, 5, 21, 20, 13, 0, time.UTC),
+		},
+		{
+			name:      "store corruption",
+			code:      0x80073712,
+			logName:   "ERROR_SXS_COMPONENT_STORE_CORRUPT",
+			matches:   1,
+			count:     2,
+			firstLine: 5,
+			lastLine:  6,
+			lastTime:  time.Date(2024, 3, 5, 21, 14, 7, 0, time.UTC),
+		},
+	}
+
+	if len(analysis.Failures) != len(tests) {
+		t.Fatalf("Analyze() found %d failures, expected %d: %+v", len(analysis.Failures), len(tests), analysis.Failures)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := analysis.Failures[i]
+
+			if failure.Code != tt.code || failure.Name != tt.logName {
+				t.Errorf("failure %d is %s (0x%08X), expected %s (0x%08X)", i, failure.Name, failure.Code, tt.logName, tt.code)
+			}
+
+			if len(failure.Matches) != tt.matches {
+				t.Errorf("failure has %d matches, expected %d", len(failure.Matches), tt.matches)
+			}
+
+			if failure.Count != tt.count || failure.First.Line != tt.firstLine || failure.Last.Line != tt.lastLine {
+				t.Errorf("failure seen %d times on lines %d to %d, expected %d times on lines %d to %d", failure.Count, failure.First.Line, failure.Last.Line, tt.count, tt.firstLine, tt.lastLine)
+			}
+
+			if !failure.Last.Time.Equal(tt.lastTime) || failure.Last.Component != "CBS" {
+				t.Errorf("failure last seen at %v in %s, expected %v in CBS", failure.Last.Time, failure.Last.Component, tt.lastTime)
+			}
+		})
+	}
+}
+
+func TestAnalyze_NoEntries(t *testing.T) {
+	_, err := Analyze(createTestRepo(), strings.NewReader("Error 0x800F081F\nnot a log\n"))
+
+	if !errors.Is(err, ErrNoEntries) {
+		t.Errorf("Analyze() error = %v, expected %v", err, ErrNoEntries)
+	}
+}
+
+// A code repeated on one line counts once for that line.
+func TestAnalyze_RepeatedCode(t *testing.T) {
+	log := `2024-03-05 21:14:07, Error                 CBS    Failed to resolve package 'Package_for_RollupFix' [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING], last error 0x800F081F
+2024-03-05 21:20:13, Error                 CBS    Failed to execute package: Package_for_RollupFix [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]
+`
+
+	analysis, err := Analyze(createTestRepo(), strings.NewReader(log))
+
+	if err != nil {
+		t.Fatalf("Analyze() unexpected error: %v", err)
+	}
+
+	if len(analysis.Failures) != 1 {
+		t.Fatalf("Analyze() found %d failures, expected 1: %+v", len(analysis.Failures), analysis.Failures)
+	}
+
+	if failure := analysis.Failures[0]; failure.Count != 2 || failure.Name != "CBS_E_SOURCE_MISSING" {
+		t.Errorf("failure %s seen %d times, expected CBS_E_SOURCE_MISSING seen 2 times", failure.Name, failure.Count)
+	}
+}
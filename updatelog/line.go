@@ -0,0 +1,84 @@
+// Package updatelog scans the logs of Windows servicing, CBS.log, DISM.log
+// and WindowsUpdate.log, for the error codes of failed updates and repairs.
+package updatelog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Format is the kind of log a line comes from.
+type Format string
+
+const (
+	FormatCBS           Format = "CBS.log"
+	FormatDISM          Format = "DISM.log"
+	FormatWindowsUpdate Format = "WindowsUpdate.log"
+)
+
+// Entry is a line of a log.
+type Entry struct {
+	Format Format
+	// Time is the local time of the machine the log was written on
+	Time time.Time
+	// Level is empty in WindowsUpdate.log, which has no levels
+	Level     string
+	Component string
+	Message   string
+}
+
+var (
+	// 2024-03-05 21:14:07, Error                 CBS    Failed to ... [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]
+	// as in both CBS.log and DISM.log
+	cbsLineRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}), (\w+)\s+(\S+)\s+(.*)$`)
+	// 2024/03/05 21:14:07.1234567 1234  5678  Agent           ... as written
+	// by Get-WindowsUpdateLog on Windows 10 and later
+	wuLineRegex = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d+)\s+\d+\s+\d+\s+(\S+)\s+(.*)$`)
+	// 2024-03-05	21:14:07:123	 1234	 5678	Agent	  * WARNING: ... as in
+	// WindowsUpdate.log up to Windows 8.1
+	legacyWULineRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\t(\d{2}:\d{2}:\d{2}):(\d{3})\t\s*\d+\t\s*[0-9a-fA-F]+\t(\S+)\t\s*(.*)$`)
+)
+
+// ParseLine reads a line of any of the supported logs. Lines without a
+// timestamp, such as the continuations of multi-line messages, are not
+// entries.
+func ParseLine(line string) (Entry, bool) {
+	line = strings.TrimRight(line, "\r\n")
+
+	if match := cbsLineRegex.FindStringSubmatch(line); match != nil {
+		entry := Entry{Format: FormatCBS, Level: match[2], Component: match[3], Message: match[4]}
+
+		if entry.Component == "DISM" {
+			entry.Format = FormatDISM
+		}
+
+		return entry.withTime("2006-01-02 15:04:05", match[1])
+	}
+
+	if match := wuLineRegex.FindStringSubmatch(line); match != nil {
+		entry := Entry{Format: FormatWindowsUpdate, Component: match[2], Message: match[3]}
+
+		return entry.withTime("2006/01/02 15:04:05.999999999", match[1])
+	}
+
+	if match := legacyWULineRegex.FindStringSubmatch(line); match != nil {
+		entry := Entry{Format: FormatWindowsUpdate, Component: match[4], Message: match[5]}
+
+		return entry.withTime("2006-01-02 15:04:05.000", match[1]+" "+match[2]+"."+match[3])
+	}
+
+	return Entry{}, false
+}
+
+func (entry Entry) withTime(layout string, value string) (Entry, bool) {
+	parsed, err := time.Parse(layout, value)
+
+	if err != nil {
+		return Entry{}, false
+	}
+
+	entry.Time = parsed
+
+	return entry, true
+}
@@ -0,0 +1,84 @@
+package updatelog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected Entry
+		ok       bool
+	}{
+		{
+			name: "CBS.log",
+			line: "2024-03-05 21:14:07, Error                 CBS    Failed to resolve package 'Package_for_RollupFix~31bf3856ad364e35~amd64~~19041.4170.1.10' [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]\r\n",
+			expected: Entry{
+				Format:    FormatCBS,
+				Time:      time.Date(2024, 3, 5, 21, 14, 7, 0, time.UTC),
+				Level:     "Error",
+				Component: "CBS",
+				Message:   "Failed to resolve package 'Package_for_RollupFix~31bf3856ad364e35~amd64~~19041.4170.1.10' [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]",
+			},
+			ok: true,
+		},
+		{
+			name: "DISM.log",
+			line: "2024-03-05 21:15:30, Error                 DISM   DISM Package Manager: PID=4321 TID=8765 Failed finalizing changes. - CDISMPackageManager::Internal_Finalize(hr:0x800f081f)",
+			expected: Entry{
+				Format:    FormatDISM,
+				Time:      time.Date(2024, 3, 5, 21, 15, 30, 0, time.UTC),
+				Level:     "Error",
+				Component: "DISM",
+				Message:   "DISM Package Manager: PID=4321 TID=8765 Failed finalizing changes. - CDISMPackageManager::Internal_Finalize(hr:0x800f081f)",
+			},
+			ok: true,
+		},
+		{
+			name: "WindowsUpdate.log",
+			line: "2024/03/05 21:16:01.1234567 1234  5678  Agent           * END * Finding updates CallerId = UpdateOrchestrator  Exit code = 0x8024402C",
+			expected: Entry{
+				Format:    FormatWindowsUpdate,
+				Time:      time.Date(2024, 3, 5, 21, 16, 1, 123456700, time.UTC),
+				Component: "Agent",
+				Message:   "* END * Finding updates CallerId = UpdateOrchestrator  Exit code = 0x8024402C",
+			},
+			ok: true,
+		},
+		{
+			name: "legacy WindowsUpdate.log",
+			line: "2014-03-05\t21:16:01:123\t 1234\t 1a2c\tPT\t  + WARNING: Sync of Updates: 0x8024402c",
+			expected: Entry{
+				Format:    FormatWindowsUpdate,
+				Time:      time.Date(2014, 3, 5, 21, 16, 1, 123000000, time.UTC),
+				Component: "PT",
+				Message:   "+ WARNING: Sync of Updates: 0x8024402c",
+			},
+			ok: true,
+		},
+		{name: "continuation", line: "    Package_for_KB5034441~31bf3856ad364e35~amd64~~19041.3920.1.0", ok: false},
+		{name: "invalid date", line: "2024-13-45 21:14:07, Info                  CBS    Starting", ok: false},
+		{name: "empty", line: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := ParseLine(tt.line)
+
+			if ok != tt.ok || !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseLine() = %+v, %v, expected %+v, %v", result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func BenchmarkParseLine(b *testing.B) {
+	line := "2024-03-05 21:14:07, Error                 CBS    Failed to resolve package [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]"
+
+	for i := 0; i < b.N; i++ {
+		ParseLine(line)
+	}
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BugCheckLine is a bug check as reported by a blue screen, the event log
+// or the debugger.
+type BugCheckLine struct {
+	Code       uint32
+	Parameters [4]uint64
+	// Name is the symbolic name of the bug check if the report has it
+	Name string
+	// Format says where the report seems to come from, e.g. "!analyze output"
+	Format string
+}
+
+// parameterPattern matches a parameter, possibly split by a backtick like
+// the debugger prints 64-bit values
+const parameterPattern = "(?:0[xX])?[0-9a-fA-F`]+"
+
+var (
+	// STOP 0x0000007E (0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740) as on
+	// the old blue screens and in the 1001 events ("The bugcheck was: ...")
+	stopLineRegex = regexp.MustCompile(`(0[xX][0-9a-fA-F]{1,8})\s*\(\s*(` + parameterPattern + `(?:\s*,\s*` + parameterPattern + `){0,3})\s*\)`)
+	// BugCheck 9F, {3, ffffe0012ad7e880, fffff8024b9b1960, ffffe0012e2d0010}
+	// as printed by the debugger when a dump is opened
+	debuggerLineRegex = regexp.MustCompile(`(?i)\bbug\s?check\s+([0-9a-f]{1,8})\s*,\s*\{([^}]*)\}`)
+	// DRIVER_POWER_STATE_FAILURE (9f) ... Arg1: 0000000000000003, ... as
+	// printed by !analyze
+	analyzeNameRegex = regexp.MustCompile(`\b([A-Z][A-Z0-9]*(?:_[A-Z0-9]+)+)\s+\(([0-9a-fA-F]{1,8})\)`)
+	analyzeArgRegex  = regexp.MustCompile(`\bArg([1-4]):\s*(` + parameterPattern + `)`)
+	// Bugcheck code 0000009F Arguments 00000000`00000003 ... as printed by
+	// .bugcheck
+	dotBugCheckRegex = regexp.MustCompile(`(?i)\bbugcheck code\s+([0-9a-f]{1,8})\s+arguments\s+((?:` + parameterPattern + `\s*){1,4})`)
+	stopNameRegex    = regexp.MustCompile(`^\s*([A-Z][A-Z0-9]*(?:_[A-Z0-9]+)+)\b`)
+)
+
+// ParseBugCheckLine finds a bug check with its parameters in text pasted
+// from a blue screen, a 1001 event, the debugger banner, !analyze or
+// .bugcheck output. Parameters missing from the text are zero. Like in the
+// debugger, numbers without a prefix are hex.
+func ParseBugCheckLine(text string) (BugCheckLine, error) {
+	if match := analyzeNameRegex.FindStringSubmatchIndex(text); match != nil {
+		args := analyzeArgRegex.FindAllStringSubmatch(text[match[1]:], -1)
+
+		if len(args) > 0 {
+			line := BugCheckLine{Name: text[match[2]:match[3]], Format: "!analyze output"}
+
+			if err := parseBugCheckCode(text[match[4]:match[5]], &line); err != nil {
+				return BugCheckLine{}, err
+			}
+
+			for _, arg := range args {
+				index, _ := strconv.Atoi(arg[1])
+				value, err := ParseParameter(arg[2])
+
+				if err != nil {
+					return BugCheckLine{}, err
+				}
+
+				line.Parameters[index-1] = value
+			}
+
+			return line, nil
+		}
+	}
+
+	if match := dotBugCheckRegex.FindStringSubmatch(text); match != nil {
+		return parseBugCheckParts(match[1], strings.Fields(match[2]), ".bugcheck output")
+	}
+
+	if match := debuggerLineRegex.FindStringSubmatch(text); match != nil {
+		return parseBugCheckParts(match[1], strings.Split(match[2], ","), "debugger banner")
+	}
+
+	if match := stopLineRegex.FindStringSubmatchIndex(text); match != nil {
+		line, err := parseBugCheckParts(text[match[2]:match[3]], strings.Split(text[match[4]:match[5]], ","), "STOP line")
+
+		if err != nil {
+			return BugCheckLine{}, err
+		}
+
+		// old blue screens show the name right after the parameters
+		if name := stopNameRegex.FindStringSubmatch(text[match[1]:]); name != nil {
+			line.Name = name[1]
+		}
+
+		return line, nil
+	}
+
+	return BugCheckLine{}, errors.New("no bug check code with parameters found")
+}
+
+func parseBugCheckParts(code string, parameters []string, format string) (BugCheckLine, error) {
+	line := BugCheckLine{Format: format}
+
+	if err := parseBugCheckCode(code, &line); err != nil {
+		return BugCheckLine{}, err
+	}
+
+	for i, parameter := range parameters[:min(len(parameters), len(line.Parameters))] {
+		value, err := ParseParameter(parameter)
+
+		if err != nil {
+			return BugCheckLine{}, err
+		}
+
+		line.Parameters[i] = value
+	}
+
+	return line, nil
+}
+
+func parseBugCheckCode(code string, line *BugCheckLine) error {
+	value, err := ParseParameter(code)
+
+	if err != nil {
+		return err
+	}
+
+	if value > 0xFFFFFFFF {
+		return &strconv.NumError{Func: "ParseBugCheckLine", Num: code, Err: strconv.ErrRange}
+	}
+
+	line.Code = uint32(value)
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestParseBugCheckLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected BugCheckLine
+		wantErr  bool
+	}{
+		{
+			name:  "event 1001",
+			input: "The computer has rebooted from a bugcheck.  The bugcheck was: 0x0000009f (0x0000000000000003, 0xffffe0012ad7e880, 0xfffff8024b9b1960, 0xffffe0012e2d0010). A dump was saved in: C:\\Windows\\MEMORY.DMP. Report Id: 061415-20656-01.",
+			expected: BugCheckLine{
+				Code:       0x9F,
+				Parameters: [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010},
+				Format:     "STOP line",
+			},
+		},
+		{
+			name:  "STOP screen",
+			input: "*** STOP: 0x0000007E (0xC0000005,0x8054B7C9,0xF78D2A44,0xF78D2740)\nSYSTEM_THREAD_EXCEPTION_NOT_HANDLED",
+			expected: BugCheckLine{
+				Code:       0x7E,
+				Parameters: [4]uint64{0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740},
+				Name:       "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED",
+				Format:     "STOP line",
+			},
+		},
+		{
+			name:  "STOP screen with fewer parameters",
+			input: "STOP 0x000000D1 (0x00000000, 0x00000002)",
+			expected: BugCheckLine{
+				Code:       0xD1,
+				Parameters: [4]uint64{0x0, 0x2},
+				Format:     "STOP line",
+			},
+		},
+		{
+			name:  "debugger banner",
+			input: "BugCheck 133, {1, 1e00, fffff8006c2fb320, 0}",
+			expected: BugCheckLine{
+				Code:       0x133,
+				Parameters: [4]uint64{0x1, 0x1E00, 0xFFFFF8006C2FB320, 0x0},
+				Format:     "debugger banner",
+			},
+		},
+		{
+			name: "!analyze",
+			input: "DRIVER_POWER_STATE_FAILURE (9f)\n" +
+				"A driver has failed to complete a power IRP within a specific time.\n" +
+				"Arguments:\n" +
+				"Arg1: 0000000000000003, A device object has been blocking an Irp for too long a time\n" +
+				"Arg2: ffffe0012ad7e880, Physical Device Object of the stack\n" +
+				"Arg3: fffff8024b9b1960, nt!TRIAGE_9F_POWER on Win7 and higher\n" +
+				"Arg4: ffffe0012e2d0010, The blocked IRP",
+			expected: BugCheckLine{
+				Code:       0x9F,
+				Parameters: [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010},
+				Name:       "DRIVER_POWER_STATE_FAILURE",
+				Format:     "!analyze output",
+			},
+		},
+		{
+			name:  "!analyze pasted on one line",
+			input: "SYSTEM_SERVICE_EXCEPTION (3b) An exception happened. Arguments: Arg1: 00000000c0000005, Exception code Arg2: fffff8017a0d1b2c, Address Arg3: ffff8a8c`7b6e6f20, Context Arg4: 0000000000000000",
+			expected: BugCheckLine{
+				Code:       0x3B,
+				Parameters: [4]uint64{0xC0000005, 0xFFFFF8017A0D1B2C, 0xFFFF8A8C7B6E6F20, 0x0},
+				Name:       "SYSTEM_SERVICE_EXCEPTION",
+				Format:     "!analyze output",
+			},
+		},
+		{
+			name:  ".bugcheck",
+			input: "Bugcheck code 0000009F\nArguments 00000000`00000003 ffffe001`2ad7e880 fffff802`4b9b1960 ffffe001`2e2d0010",
+			expected: BugCheckLine{
+				Code:       0x9F,
+				Parameters: [4]uint64{0x3, 0xFFFFE0012AD7E880, 0xFFFFF8024B9B1960, 0xFFFFE0012E2D0010},
+				Format:     ".bugcheck output",
+			},
+		},
+		{name: "no bug check", input: "The system cannot find the file specified.", wantErr: true},
+		{name: "code too long", input: "BugCheck 1ffffffff, {1, 2, 3, 4}", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseBugCheckLine(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseBugCheckLine(%q) = %+v, expected error", tt.input, result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseBugCheckLine(%q) unexpected error: %v", tt.input, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("ParseBugCheckLine(%q) = %+v, expected %+v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkParseBugCheckLine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParseBugCheckLine("The bugcheck was: 0x0000009f (0x0000000000000003, 0xffffe0012ad7e880, 0xfffff8024b9b1960, 0xffffe0012e2d0010).")
+	}
+}
+
+func ExampleParseBugCheckLine() {
+	line, _ := ParseBugCheckLine("*** STOP: 0x0000007E (0xC0000005, 0x8054B7C9, 0xF78D2A44, 0xF78D2740)")
+	println(line.Code, line.Parameters[0]) // 126 3221225477
+}
@@ -0,0 +1,270 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+)
+
+// CodeMatch is an error code found in free text.
+type CodeMatch struct {
+	// Text is the code as it was written, e.g. 0xC0000005 or E_FAIL
+	Text string
+	// Offset is the position of Text in the scanned text, in bytes
+	Offset int
+	// Codes holds the possible values of a numeric code; it is empty for
+	// symbolic names
+	Codes []uint32
+	// Kinds lists the catalogs the code most likely comes from, best guess
+	// first; it is empty if nothing hints at one
+	Kinds []winerror.Kind
+}
+
+// IsName reports whether the match is a symbolic name rather than a number.
+func (match CodeMatch) IsName() bool {
+	return len(match.Codes) == 0
+}
+
+var (
+	codeTokenPattern = regexp.MustCompile(`-?[0-9A-Za-z_]+`)
+	namePattern      = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)+$`)
+)
+
+// contextLength is how far before a code to look for words hinting at its
+// catalog, in bytes
+const contextLength = 40
+
+// codeHint is a word that tells which catalog the code following it is
+// from. A hint without kinds only says that a number is a code and leaves
+// the guess to its value.
+type codeHint struct {
+	kinds []winerror.Kind
+}
+
+var contextHints = map[string]codeHint{
+	"stop":          {[]winerror.Kind{winerror.KindBugCheck}},
+	"bugcheck":      {[]winerror.Kind{winerror.KindBugCheck}},
+	"bug check":     {[]winerror.Kind{winerror.KindBugCheck}},
+	"stop code":     {[]winerror.Kind{winerror.KindBugCheck}},
+	"bugcheck code": {[]winerror.Kind{winerror.KindBugCheck}},
+	"bccode":        {[]winerror.Kind{winerror.KindBugCheck}},
+	"bsod":          {[]winerror.Kind{winerror.KindBugCheck}},
+	"hresult":       {[]winerror.Kind{winerror.KindHResult}},
+	"hr":            {[]winerror.Kind{winerror.KindHResult}},
+	"ntstatus":      {[]winerror.Kind{winerror.KindNTStatus}},
+	"status":        {[]winerror.Kind{winerror.KindNTStatus}},
+	"exception":     {[]winerror.Kind{winerror.KindNTStatus}},
+	"win32":         {[]winerror.Kind{winerror.KindWin32Error}},
+	"getlasterror":  {[]winerror.Kind{winerror.KindWin32Error}},
+	"lasterror":     {[]winerror.Kind{winerror.KindWin32Error}},
+	"system error":  {[]winerror.Kind{winerror.KindWin32Error}},
+	"exit code":     {},
+	"exitcode":      {},
+	"exit status":   {},
+	"return code":   {},
+	"error code":    {},
+	"error":         {},
+}
+
+// ExtractCodes finds everything in text that looks like an error code and
+// guesses which catalog it is from.
+//
+// Hex numbers with a 0x prefix (optionally with the L and U suffixes of C
+// literals), bare 8-digit hex numbers with the high bit set, 9 and 10-digit
+// decimal numbers and upper-case symbolic names such as E_FAIL are always
+// recognized. Shorter numbers are only recognized after words such as
+// "exit code" or "STOP" that say a code follows. Each code is only reported
+// the first time it appears.
+func ExtractCodes(text string) []CodeMatch {
+	matches := []CodeMatch{}
+	seen := map[string]bool{}
+
+	for _, location := range codeTokenPattern.FindAllStringIndex(text, -1) {
+		start, end := location[0], location[1]
+
+		// a dash right after a word is a separator, not a sign
+		if text[start] == '-' && start > 0 && isWordByte(text[start-1]) {
+			start++
+		}
+
+		token := text[start:end]
+		hint, hinted := findHint(text[:start])
+		codes, ok := classifyToken(token, hinted)
+
+		if !ok {
+			continue
+		}
+
+		key := strings.ToUpper(token)
+
+		if len(codes) > 0 {
+			key = strconv.FormatUint(uint64(codes[0]), 16)
+		}
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		// the prefix of a name says more than the words before it
+		kinds := hint.kinds
+
+		if guess := guessKinds(token, codes); len(guess) > 0 && (len(kinds) == 0 || len(codes) == 0) {
+			kinds = guess
+		}
+
+		matches = append(matches, CodeMatch{
+			Text:   token,
+			Offset: start,
+			Codes:  codes,
+			Kinds:  kinds,
+		})
+	}
+
+	return matches
+}
+
+// findHint looks for a hint among the last words before a code on the same
+// line, the closest one winning.
+func findHint(before string) (codeHint, bool) {
+	if i := strings.LastIndexByte(before, '\n'); i >= 0 {
+		before = before[i+1:]
+	}
+
+	if len(before) > contextLength {
+		before = before[len(before)-contextLength:]
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(before), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	// only the words right before the code count, "STOP 0x7E" is a bug
+	// check but "STOP by 5 pm" is not
+	if len(words) == 0 {
+		return codeHint{}, false
+	}
+
+	last := words[len(words)-1]
+
+	if len(words) > 1 {
+		if hint, ok := contextHints[words[len(words)-2]+" "+last]; ok {
+			return hint, true
+		}
+	}
+
+	if hint, ok := contextHints[last]; ok {
+		return hint, true
+	}
+
+	return codeHint{}, false
+}
+
+// classifyToken decides whether a single word looks like an error code and
+// returns its possible values. Hinted tokens are accepted even if they are
+// short numbers.
+func classifyToken(token string, hinted bool) ([]uint32, bool) {
+	digits := strings.TrimPrefix(token, "-")
+
+	switch {
+	case strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0X"):
+		codes, err := ParseCode(token)
+		return codes, err == nil
+
+	case isDecimal(digits) && (hinted || len(digits) == 9 || len(digits) == 10):
+		codes, err := ParseCode(token)
+
+		if err != nil {
+			return nil, false
+		}
+
+		// without a prefix, the number is read as decimal unless it is 8
+		// digits long with the high bit set, which is how hex codes look
+		if len(token) == 8 && token[0] >= '8' {
+			return codes[:1], true
+		}
+
+		return codes[len(codes)-1:], true
+
+	case len(token) == 8 && isHex(token) && strings.IndexByte("89ABCDEFabcdef", token[0]) >= 0:
+		// bare hex codes are only recognized with the high bit set, which
+		// keeps dates and other numbers out
+		value, err := strconv.ParseUint(token, 16, 32)
+		return []uint32{uint32(value)}, err == nil
+
+	case hinted && isHex(token) && len(token) <= 8 && strings.ContainsAny(token, "0123456789"):
+		value, err := strconv.ParseUint(token, 16, 32)
+		return []uint32{uint32(value)}, err == nil
+
+	case namePattern.MatchString(token):
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// guessKinds guesses the catalog of a code without any context from its name
+// prefix or the shape of its value.
+func guessKinds(token string, codes []uint32) []winerror.Kind {
+	if len(codes) == 0 {
+		switch {
+		case strings.HasPrefix(token, "STATUS_"):
+			return []winerror.Kind{winerror.KindNTStatus}
+		case strings.HasPrefix(token, "ERROR_"):
+			return []winerror.Kind{winerror.KindWin32Error}
+		case strings.HasPrefix(token, "E_") || strings.HasPrefix(token, "S_") || strings.Contains(token, "_E_"):
+			return []winerror.Kind{winerror.KindHResult}
+		}
+
+		return nil
+	}
+
+	code := codes[0]
+
+	switch {
+	case code>>28 == 0xC || code>>28 == 0xD:
+		// error severity with the customer bit either way
+		return []winerror.Kind{winerror.KindNTStatus, winerror.KindHResult}
+	case code>>28 == 0x8:
+		return []winerror.Kind{winerror.KindHResult, winerror.KindNTStatus}
+	case code>>28 == 0x1:
+		// bug checks with the 0x10000000 flag, e.g. 0x1000007E
+		return []winerror.Kind{winerror.KindBugCheck}
+	case code <= 0xFFFF:
+		return []winerror.Kind{winerror.KindWin32Error, winerror.KindBugCheck}
+	}
+
+	return nil
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isDecimal(text string) bool {
+	if text == "" {
+		return false
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isHex(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte("0123456789ABCDEFabcdef", text[i]) < 0 {
+			return false
+		}
+	}
+
+	return text != ""
+}
@@ -0,0 +1,179 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+)
+
+var (
+	bugCheckKinds = []winerror.Kind{winerror.KindBugCheck}
+	hResultKinds  = []winerror.Kind{winerror.KindHResult}
+	win32Kinds    = []winerror.Kind{winerror.KindWin32Error}
+	ntStatusKinds = []winerror.Kind{winerror.KindNTStatus}
+	errorKinds    = []winerror.Kind{winerror.KindNTStatus, winerror.KindHResult}
+	failureKinds  = []winerror.Kind{winerror.KindHResult, winerror.KindNTStatus}
+	smallKinds    = []winerror.Kind{winerror.KindWin32Error, winerror.KindBugCheck}
+)
+
+func TestExtractCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []CodeMatch
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: []CodeMatch{},
+		},
+		{
+			name:     "hex with prefix",
+			input:    "The app crashed with 0xC0000005.",
+			expected: []CodeMatch{{Text: "0xC0000005", Offset: 21, Codes: []uint32{0xC0000005}, Kinds: errorKinds}},
+		},
+		{
+			name:     "hex with literal suffix",
+			input:    "#define E_ACCESSDENIED _HRESULT_TYPEDEF_(0x80070005L)",
+			expected: []CodeMatch{{Text: "E_ACCESSDENIED", Offset: 8, Kinds: hResultKinds}, {Text: "0x80070005L", Offset: 41, Codes: []uint32{0x80070005}, Kinds: failureKinds}},
+		},
+		{
+			name:     "hex in parentheses",
+			input:    "The operation failed (0x800F081F)",
+			expected: []CodeMatch{{Text: "0x800F081F", Offset: 22, Codes: []uint32{0x800F081F}, Kinds: failureKinds}},
+		},
+		{
+			name:     "after error",
+			input:    "Error 0x80070002 occurred",
+			expected: []CodeMatch{{Text: "0x80070002", Offset: 6, Codes: []uint32{0x80070002}, Kinds: failureKinds}},
+		},
+		{
+			name:     "bare hex",
+			input:    "code C0000005 again",
+			expected: []CodeMatch{{Text: "C0000005", Offset: 5, Codes: []uint32{0xC0000005}, Kinds: errorKinds}},
+		},
+		{
+			name:     "bare hex without high bit",
+			input:    "on 20241017 at 10:00",
+			expected: []CodeMatch{},
+		},
+		{
+			name:     "negative decimal",
+			input:    "returned -2147024891",
+			expected: []CodeMatch{{Text: "-2147024891", Offset: 9, Codes: []uint32{0x80070005}, Kinds: failureKinds}},
+		},
+		{
+			name:     "exit code",
+			input:    "Exit code: 3221225477",
+			expected: []CodeMatch{{Text: "3221225477", Offset: 11, Codes: []uint32{0xC0000005}, Kinds: errorKinds}},
+		},
+		{
+			name:     "short exit code",
+			input:    "Process exited with exit code 5",
+			expected: []CodeMatch{{Text: "5", Offset: 30, Codes: []uint32{5}, Kinds: smallKinds}},
+		},
+		{
+			name:     "short decimal",
+			input:    "line 42 of 1000",
+			expected: []CodeMatch{},
+		},
+		{
+			name:     "stop code",
+			input:    "STOP 0x0000007E (0xC0000005, 0xFFFFF80312345678)",
+			expected: []CodeMatch{{Text: "0x0000007E", Offset: 5, Codes: []uint32{0x7E}, Kinds: bugCheckKinds}, {Text: "0xC0000005", Offset: 17, Codes: []uint32{0xC0000005}, Kinds: errorKinds}},
+		},
+		{
+			name:     "stop code without prefix",
+			input:    "BugCheck 7E",
+			expected: []CodeMatch{{Text: "7E", Offset: 9, Codes: []uint32{0x7E}, Kinds: bugCheckKinds}},
+		},
+		{
+			name:     "hint is only the word right before",
+			input:    "stop by at 5",
+			expected: []CodeMatch{},
+		},
+		{
+			name:     "hinted words without digits",
+			input:    "error bad",
+			expected: []CodeMatch{},
+		},
+		{
+			name:     "hresult",
+			input:    "hr = 0xC0000022",
+			expected: []CodeMatch{{Text: "0xC0000022", Offset: 5, Codes: []uint32{0xC0000022}, Kinds: hResultKinds}},
+		},
+		{
+			name:     "ntstatus",
+			input:    "Status: 0x80000005",
+			expected: []CodeMatch{{Text: "0x80000005", Offset: 8, Codes: []uint32{0x80000005}, Kinds: ntStatusKinds}},
+		},
+		{
+			name:     "win32",
+			input:    "GetLastError() = 1722",
+			expected: []CodeMatch{{Text: "1722", Offset: 17, Codes: []uint32{1722}, Kinds: win32Kinds}},
+		},
+		{
+			name:     "dash after word",
+			input:    "build-123456789",
+			expected: []CodeMatch{{Text: "123456789", Offset: 6, Codes: []uint32{123456789}}},
+		},
+		{
+			name:     "symbolic name",
+			input:    "CreateFile failed with ERROR_ACCESS_DENIED",
+			expected: []CodeMatch{{Text: "ERROR_ACCESS_DENIED", Offset: 23, Kinds: win32Kinds}},
+		},
+		{
+			name:     "name prefix beats context",
+			input:    "status ERROR_ACCESS_DENIED",
+			expected: []CodeMatch{{Text: "ERROR_ACCESS_DENIED", Offset: 7, Kinds: win32Kinds}},
+		},
+		{
+			name:     "bug check name",
+			input:    "Stop code: SYSTEM_THREAD_EXCEPTION_NOT_HANDLED",
+			expected: []CodeMatch{{Text: "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED", Offset: 11, Kinds: bugCheckKinds}},
+		},
+		{
+			name:     "lower-case words are not names",
+			input:    "some_variable_name",
+			expected: []CodeMatch{},
+		},
+		{
+			name:  "duplicates are reported once",
+			input: "0xC0000005 3221225477 0xc0000005 E_FAIL e_fail E_FAIL",
+			expected: []CodeMatch{
+				{Text: "0xC0000005", Offset: 0, Codes: []uint32{0xC0000005}, Kinds: errorKinds},
+				{Text: "E_FAIL", Offset: 33, Kinds: hResultKinds},
+			},
+		},
+		{
+			name:     "too long for 32 bits",
+			input:    "at 0xFFFFF80012345678",
+			expected: []CodeMatch{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractCodes(tt.input)
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ExtractCodes(%q) = %+v, expected %+v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkExtractCodes(b *testing.B) {
+	text := "2024-10-17 10:00:00, Error CBS Failed to resolve package [HRESULT = 0x800f081f - CBS_E_SOURCE_MISSING]\n"
+
+	for i := 0; i < b.N; i++ {
+		ExtractCodes(text)
+	}
+}
+
+func ExampleExtractCodes() {
+	for _, match := range ExtractCodes("STOP 0x0000007E, then E_FAIL") {
+		println(match.Text, match.Kinds[0].String()) // 0x0000007E bug check, then E_FAIL HRESULT
+	}
+}
@@ -0,0 +1,187 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dhrdlicka/errorbot/winerror"
+)
+
+// NameResolver returns the code of a symbolic name such as E_FAIL.
+type NameResolver func(name string) (uint32, bool)
+
+type macro struct {
+	arity int
+	apply func(args []uint32) uint32
+}
+
+var macros = map[string]macro{
+	"HRESULT_FROM_WIN32": {1, func(args []uint32) uint32 {
+		return uint32(winerror.HResultFromWin32(args[0]))
+	}},
+	"__HRESULT_FROM_WIN32": {1, func(args []uint32) uint32 {
+		return uint32(winerror.HResultFromWin32(args[0]))
+	}},
+	"HRESULT_FROM_NT": {1, func(args []uint32) uint32 {
+		return uint32(winerror.HResultFromNT(args[0]))
+	}},
+	"NTSTATUS_FROM_WIN32": {1, func(args []uint32) uint32 {
+		return uint32(winerror.NTStatusFromWin32(args[0]))
+	}},
+	"MAKE_HRESULT": {3, func(args []uint32) uint32 {
+		return uint32(winerror.MakeHResult(args[0], args[1], args[2]))
+	}},
+	"HRESULT_CODE": {1, func(args []uint32) uint32 {
+		return uint32(winerror.HResult(args[0]).Code())
+	}},
+}
+
+var macroPattern = regexp.MustCompile(`^\s*[A-Za-z_][A-Za-z0-9_]*\s*\(`)
+
+// IsMacro reports whether expression looks like a macro call such as
+// HRESULT_FROM_WIN32(5).
+func IsMacro(expression string) bool {
+	return macroPattern.MatchString(expression)
+}
+
+// EvaluateMacro computes the code an expression made of the error macros
+// from the Windows headers stands for, e.g. HRESULT_FROM_WIN32(5) or
+// MAKE_HRESULT(1, 7, ERROR_ACCESS_DENIED). Calls can be nested. Arguments
+// can be decimal numbers, hex numbers with a 0x prefix or symbolic names
+// looked up through resolve, as in C.
+func EvaluateMacro(expression string, resolve NameResolver) (uint32, error) {
+	parser := macroParser{
+		text:    expression,
+		resolve: resolve,
+	}
+
+	code, err := parser.expression()
+
+	if err != nil {
+		return 0, err
+	}
+
+	parser.skipSpace()
+
+	if parser.position < len(parser.text) {
+		return 0, fmt.Errorf("unexpected %q after the expression", parser.text[parser.position:])
+	}
+
+	return code, nil
+}
+
+type macroParser struct {
+	text     string
+	position int
+	resolve  NameResolver
+}
+
+func (parser *macroParser) skipSpace() {
+	for parser.position < len(parser.text) && strings.IndexByte(" \t\r\n", parser.text[parser.position]) >= 0 {
+		parser.position++
+	}
+}
+
+func (parser *macroParser) next(c byte) bool {
+	parser.skipSpace()
+
+	if parser.position < len(parser.text) && parser.text[parser.position] == c {
+		parser.position++
+		return true
+	}
+
+	return false
+}
+
+func (parser *macroParser) expression() (uint32, error) {
+	parser.skipSpace()
+
+	start := parser.position
+
+	for parser.position < len(parser.text) && strings.IndexByte("(), \t\r\n", parser.text[parser.position]) < 0 {
+		parser.position++
+	}
+
+	token := parser.text[start:parser.position]
+
+	if token == "" {
+		return 0, errors.New("an argument is missing")
+	}
+
+	if parser.next('(') {
+		return parser.call(token)
+	}
+
+	return parser.value(token)
+}
+
+func (parser *macroParser) call(name string) (uint32, error) {
+	macro, ok := macros[strings.ToUpper(name)]
+
+	if !ok {
+		return 0, fmt.Errorf("%s is not a known macro", name)
+	}
+
+	args := []uint32{}
+
+	if !parser.next(')') {
+		for {
+			arg, err := parser.expression()
+
+			if err != nil {
+				return 0, err
+			}
+
+			args = append(args, arg)
+
+			if parser.next(',') {
+				continue
+			}
+
+			if parser.next(')') {
+				break
+			}
+
+			return 0, fmt.Errorf("the arguments of %s are not closed with )", name)
+		}
+	}
+
+	if len(args) != macro.arity {
+		return 0, fmt.Errorf("%s takes %d arguments, not %d", name, macro.arity, len(args))
+	}
+
+	return macro.apply(args), nil
+}
+
+func (parser *macroParser) value(token string) (uint32, error) {
+	identifier := token[0] == '_' || 'A' <= token[0] && token[0] <= 'Z' || 'a' <= token[0] && token[0] <= 'z'
+
+	// anything starting with a letter is a name, as it is in C, so a
+	// mistyped name is not read as a hex number
+	if identifier {
+		if parser.resolve != nil {
+			if code, ok := parser.resolve(token); ok {
+				return code, nil
+			}
+		}
+
+		return 0, fmt.Errorf("%s is not a known symbolic name", token)
+	}
+
+	// hex numbers need their 0x prefix, everything else is decimal
+	literal := token
+
+	if prefix := strings.ToLower(token[:min(2, len(token))]); prefix != "0x" && prefix != "0n" {
+		literal = "0n" + token
+	}
+
+	interpretations, err := InterpretCode(literal)
+
+	if err != nil {
+		return 0, fmt.Errorf("cannot read %s as a decimal number or a hex number with a 0x prefix: %w", token, err)
+	}
+
+	return interpretations[0].Code, nil
+}
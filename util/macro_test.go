@@ -0,0 +1,105 @@
+package util
+
+import (
+	"testing"
+)
+
+func testResolver(name string) (uint32, bool) {
+	codes := map[string]uint32{
+		"ERROR_ACCESS_DENIED":     5,
+		"STATUS_ACCESS_VIOLATION": 0xC0000005,
+		"E_ACCESSDENIED":          0x80070005,
+		"FACILITY_WIN32":          7,
+	}
+
+	code, ok := codes[name]
+
+	return code, ok
+}
+
+func TestIsMacro(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "macro", input: "HRESULT_FROM_WIN32(5)", expected: true},
+		{name: "space before parenthesis", input: " HRESULT_FROM_NT (0xC0000005)", expected: true},
+		{name: "number", input: "0xC0000005", expected: false},
+		{name: "name", input: "E_FAIL", expected: false},
+		{name: "empty", input: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsMacro(tt.input); result != tt.expected {
+				t.Errorf("IsMacro(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateMacro(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected uint32
+		wantErr  bool
+	}{
+		{name: "HRESULT_FROM_WIN32 number", input: "HRESULT_FROM_WIN32(5)", expected: 0x80070005},
+		{name: "HRESULT_FROM_WIN32 name", input: "HRESULT_FROM_WIN32(ERROR_ACCESS_DENIED)", expected: 0x80070005},
+		{name: "bare numbers are decimal", input: "HRESULT_FROM_WIN32(10)", expected: 0x8007000A},
+		{name: "hex argument", input: "HRESULT_FROM_WIN32(0x10)", expected: 0x80070010},
+		{name: "inline variant", input: "__HRESULT_FROM_WIN32(5)", expected: 0x80070005},
+		{name: "HRESULT_FROM_NT", input: "HRESULT_FROM_NT(STATUS_ACCESS_VIOLATION)", expected: 0xD0000005},
+		{name: "NTSTATUS_FROM_WIN32", input: "NTSTATUS_FROM_WIN32(ERROR_ACCESS_DENIED)", expected: 0xC0070005},
+		{name: "MAKE_HRESULT", input: "MAKE_HRESULT(1, FACILITY_WIN32, 5)", expected: 0x80070005},
+		{name: "HRESULT_CODE", input: "HRESULT_CODE(E_ACCESSDENIED)", expected: 5},
+		{name: "nested", input: "NTSTATUS_FROM_WIN32(HRESULT_CODE(0x80070005))", expected: 0xC0070005},
+		{name: "lower case macro", input: "hresult_from_win32(5)", expected: 0x80070005},
+		{name: "spaces", input: "  MAKE_HRESULT ( 1 ,7, 5 )  ", expected: 0x80070005},
+		{name: "unknown macro", input: "FROBNICATE(5)", wantErr: true},
+		{name: "unknown name", input: "HRESULT_FROM_WIN32(ERROR_NOPE)", wantErr: true},
+		{name: "bad number", input: "HRESULT_FROM_WIN32(0xZZ)", wantErr: true},
+		{name: "unknown name that is hex", input: "HRESULT_FROM_WIN32(DEAD)", wantErr: true},
+		{name: "hex without prefix", input: "HRESULT_FROM_WIN32(5A)", wantErr: true},
+		{name: "decimal suffix", input: "HRESULT_FROM_WIN32(5L)", expected: 0x80070005},
+		{name: "wrong arity", input: "MAKE_HRESULT(1, 7)", wantErr: true},
+		{name: "no arguments", input: "HRESULT_FROM_WIN32()", wantErr: true},
+		{name: "missing argument", input: "MAKE_HRESULT(1, , 5)", wantErr: true},
+		{name: "unclosed", input: "HRESULT_FROM_WIN32(5", wantErr: true},
+		{name: "trailing text", input: "HRESULT_FROM_WIN32(5) + 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateMacro(tt.input, testResolver)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("EvaluateMacro(%q) = 0x%08X, expected error", tt.input, result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("EvaluateMacro(%q) unexpected error: %v", tt.input, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("EvaluateMacro(%q) = 0x%08X, expected 0x%08X", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkEvaluateMacro(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		EvaluateMacro("NTSTATUS_FROM_WIN32(HRESULT_CODE(0x80070005))", testResolver)
+	}
+}
+
+func ExampleEvaluateMacro() {
+	code, _ := EvaluateMacro("HRESULT_FROM_WIN32(5)", nil)
+	println(code) // 2147942405 (0x80070005)
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unicode/utf16"
+)
+
+// DecodeText converts text files saved by Windows tools to UTF-8. Files
+// starting with a UTF-16 byte order mark, which is what PowerShell 5 writes
+// when output is redirected, are converted; the byte order mark of UTF-8
+// files is dropped; anything else is returned as it is.
+func DecodeText(data []byte) []byte {
+	var order binary.ByteOrder
+
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		order = binary.BigEndian
+	default:
+		return bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	}
+
+	units := make([]uint16, (len(data)-2)/2)
+
+	for i := range units {
+		units[i] = order.Uint16(data[2+i*2:])
+	}
+
+	return []byte(string(utf16.Decode(units)))
+}
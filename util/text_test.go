@@ -0,0 +1,26 @@
+package util
+
+import "testing"
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{name: "UTF-8", input: []byte("Error 0x800F081F"), expected: "Error 0x800F081F"},
+		{name: "UTF-8 with BOM", input: []byte("\xEF\xBB\xBFError"), expected: "Error"},
+		{name: "UTF-16LE", input: []byte{0xFF, 0xFE, 'O', 0, 'K', 0, 0xAC, 0x20}, expected: "OK€"},
+		{name: "UTF-16BE", input: []byte{0xFE, 0xFF, 0, 'O', 0, 'K'}, expected: "OK"},
+		{name: "odd length", input: []byte{0xFF, 0xFE, 'O', 0, 'K'}, expected: "O"},
+		{name: "empty", input: []byte{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := string(DecodeText(tt.input)); result != tt.expected {
+				t.Errorf("DecodeText() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
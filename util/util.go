@@ -2,59 +2,205 @@ package util
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"slices"
 	"strconv"
 	"strings"
 )
 
+// Interpretation is one way of reading a code literal.
+type Interpretation struct {
+	Code uint32
+	// Format says how the literal was read, e.g. "hexadecimal" or
+	// "decimal (WinDbg 0n prefix)"
+	Format string
+}
+
+// ParseCode reads a code literal and returns all the codes it may stand for.
+// See InterpretCode for the accepted formats.
 func ParseCode(code string) ([]uint32, error) {
+	interpretations, err := InterpretCode(code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]uint32, len(interpretations))
+
+	for i, interpretation := range interpretations {
+		codes[i] = interpretation.Code
+	}
+
+	return codes, nil
+}
+
+// InterpretCode reads a code literal the way it may have been pasted from a
+// debugger, a C header or a log and returns all the 32-bit codes it may
+// stand for along with how each one was read.
+//
+// Besides plain hex (0xC0000005, C0000005) and decimal (3221225477,
+// -1073741819) numbers, it understands WinDbg decimal (0n5) and
+// backtick-separated 64-bit (0xffffffff`c0000005) values, C suffixes
+// (0x80070005L, 5UL), assembler-style hex (C0000005h, #C0000005) and 64-bit
+// values sign-extended from 32 bits. Numbers without a prefix or suffix are
+// read both as hex and decimal.
+func InterpretCode(code string) ([]Interpretation, error) {
+	code = strings.TrimSpace(code)
+
 	if len(code) == 0 {
 		return nil, errors.New("empty string")
 	}
 
-	if strings.HasPrefix(code, "0x") || strings.HasPrefix(code, "0X") {
+	var notes []string
+
+	// WinDbg separates the upper and lower halves of 64-bit values
+	if strings.Contains(code, "`") {
+		code = strings.ReplaceAll(code, "`", "")
+		notes = append(notes, "WinDbg 64-bit value")
+	}
+
+	lower := strings.ToLower(code)
+
+	switch {
+	case strings.HasPrefix(lower, "0x"):
 		// hex prefix, we are almost there
-		longCode, err := strconv.ParseUint(code[2:], 16, 32)
+		digits, suffix := trimLiteralSuffix(code[2:])
+		return interpret(digits, 16, "hexadecimal", withSuffix(notes, suffix))
+
+	case strings.HasPrefix(lower, "0n"):
+		digits, suffix := trimLiteralSuffix(code[2:])
+		return interpret(digits, 10, "decimal", withSuffix(append(notes, "WinDbg 0n prefix"), suffix))
+
+	case strings.HasPrefix(code, "#") || strings.HasSuffix(code, "#"):
+		return interpret(strings.Trim(code, "#"), 16, "hexadecimal", append(notes, "# notation"))
+
+	case strings.HasSuffix(lower, "h") && isHex(code[:len(code)-1]):
+		return interpret(code[:len(code)-1], 16, "hexadecimal", append(notes, "h suffix"))
+
+	case code[0] == '-':
+		// negative number, probably signed decimal
+		digits, suffix := trimLiteralSuffix(code)
+		return interpret(digits, 10, "signed decimal", withSuffix(notes, suffix))
+	}
+
+	// C literals without a prefix are decimal
+	if digits, suffix := trimLiteralSuffix(code); suffix != "" && isDecimal(digits) {
+		return interpret(digits, 10, "decimal", withSuffix(notes, suffix))
+	}
+
+	// no prefix, now the real fun begins
+	var interpretations []Interpretation
+	var lastErr error
+
+	for _, base := range []int{16, 10} {
+		format := "hexadecimal"
+
+		if base == 10 {
+			format = "decimal"
+		}
+
+		found, err := interpret(code, base, format, notes)
 
 		if err != nil {
-			return nil, err
+			lastErr = err
+			continue
+		}
+
+		if !slices.ContainsFunc(interpretations, func(other Interpretation) bool { return other.Code == found[0].Code }) {
+			interpretations = append(interpretations, found[0])
 		}
+	}
 
-		return []uint32{uint32(longCode)}, nil
+	if len(interpretations) == 0 {
+		return nil, lastErr
 	}
 
-	if code[0] == '-' {
-		// negative number, probably signed decimal
-		intCode, err := strconv.ParseInt(code, 10, 32)
+	return interpretations, nil
+}
+
+// ParseParameter reads a bug check parameter. Parameters are 64-bit values
+// that debuggers print in hex, so numbers without a prefix are read as hex
+// like WinDbg does, and 0n marks decimal numbers.
+func ParseParameter(value string) (uint64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "`", "")
+	lower := strings.ToLower(value)
+
+	switch {
+	case value == "":
+		return 0, errors.New("empty string")
+	case strings.HasPrefix(lower, "0x"):
+		return strconv.ParseUint(value[2:], 16, 64)
+	case strings.HasPrefix(lower, "0n"):
+		return strconv.ParseUint(value[2:], 10, 64)
+	}
+
+	return strconv.ParseUint(value, 16, 64)
+}
+
+// interpret parses digits in the given base as a 32-bit code. 64-bit values
+// are accepted if they are 32-bit values sign-extended, which is how
+// debuggers show codes stored in 64-bit registers.
+func interpret(digits string, base int, format string, notes []string) ([]Interpretation, error) {
+	var value uint64
+
+	if base == 10 && strings.HasPrefix(digits, "-") {
+		signed, err := strconv.ParseInt(digits, base, 64)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if signed < math.MinInt32 {
+			return nil, &strconv.NumError{Func: "ParseInt", Num: digits, Err: strconv.ErrRange}
+		}
+
+		value = uint64(signed)
+	} else {
+		unsigned, err := strconv.ParseUint(digits, base, 64)
 
 		if err != nil {
 			return nil, err
 		}
 
-		return []uint32{uint32(intCode)}, nil
+		value = unsigned
 	}
 
-	// no prefix, now the real fun begins
-	var codes []uint32
-	var lastErr error
+	switch {
+	case value <= math.MaxUint32:
+	case value>>32 == math.MaxUint32 && value&0x80000000 != 0:
+		if !strings.HasPrefix(digits, "-") {
+			notes = append(notes, "sign-extended from 64 bits")
+		}
+	default:
+		return nil, &strconv.NumError{Func: "ParseUint", Num: digits, Err: strconv.ErrRange}
+	}
 
-	if hexCode, err := strconv.ParseUint(code, 16, 32); err == nil {
-		codes = append(codes, uint32(hexCode))
-	} else {
-		lastErr = err
+	if len(notes) > 0 {
+		format = fmt.Sprintf("%s (%s)", format, strings.Join(notes, ", "))
 	}
 
-	if decCode, err := strconv.ParseUint(code, 10, 32); err == nil {
-		codes = append(codes, uint32(decCode))
-	} else {
-		lastErr = err
+	return []Interpretation{{Code: uint32(value), Format: format}}, nil
+}
+
+// trimLiteralSuffix splits the U, L, UL, LL and ULL suffixes of C literals
+// off digits.
+func trimLiteralSuffix(digits string) (string, string) {
+	trimmed := strings.TrimRight(digits, "LlUu")
+
+	if len(digits)-len(trimmed) > 3 {
+		return digits, ""
 	}
 
-	if len(codes) == 0 {
-		return nil, lastErr
+	return trimmed, digits[len(trimmed):]
+}
+
+func withSuffix(notes []string, suffix string) []string {
+	if suffix == "" {
+		return notes
 	}
 
-	return slices.Compact(codes), nil
+	return append(notes, "C suffix "+strings.ToUpper(suffix))
 }
 
 func BoolToInt(value bool) int {
@@ -151,6 +151,110 @@ func TestParseCode(t *testing.T) {
 			wantErr:  true,
 		},
 
+		// Debugger and C literals
+		{
+			name:     "WinDbg decimal prefix",
+			input:    "0n5",
+			expected: []uint32{5},
+			wantErr:  false,
+		},
+		{
+			name:     "WinDbg decimal prefix - negative",
+			input:    "0n-1073741819",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "WinDbg backtick-separated 64-bit value",
+			input:    "0xffffffff`c0000005",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "WinDbg backtick-separated value without prefix",
+			input:    "ffffffff`c0000005",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "C suffix on hex",
+			input:    "0x80070005L",
+			expected: []uint32{0x80070005},
+			wantErr:  false,
+		},
+		{
+			name:     "C suffix on decimal",
+			input:    "5UL",
+			expected: []uint32{5},
+			wantErr:  false,
+		},
+		{
+			name:     "C suffix makes a bare number decimal",
+			input:    "123L",
+			expected: []uint32{123},
+			wantErr:  false,
+		},
+		{
+			name:     "sign-extended 64-bit hex",
+			input:    "0xFFFFFFFFC0000005",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "sign-extended 64-bit decimal",
+			input:    "18446744072635809797",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "64-bit hex that is not sign-extended",
+			input:    "0xFFFFFFFF40000005",
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "64-bit hex with upper bits set",
+			input:    "0x1C0000005",
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "h suffix",
+			input:    "C0000005h",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "# prefix",
+			input:    "#C0000005",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "# suffix",
+			input:    "C0000005#",
+			expected: []uint32{0xC0000005},
+			wantErr:  false,
+		},
+		{
+			name:     "negative hex is not accepted",
+			input:    "0x-5",
+			expected: nil,
+			wantErr:  true,
+		},
+		{
+			name:     "surrounding whitespace",
+			input:    "  0x5  ",
+			expected: []uint32{5},
+			wantErr:  false,
+		},
+		{
+			name:     "too many suffix letters",
+			input:    "5ULLL",
+			expected: nil,
+			wantErr:  true,
+		},
+
 		// Edge cases
 		{
 			name:     "single character hex",
@@ -207,6 +311,103 @@ func TestParseCode(t *testing.T) {
 	}
 }
 
+func TestInterpretCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Interpretation
+	}{
+		{
+			name:     "hex prefix",
+			input:    "0xC0000005",
+			expected: []Interpretation{{Code: 0xC0000005, Format: "hexadecimal"}},
+		},
+		{
+			name:     "ambiguous",
+			input:    "123",
+			expected: []Interpretation{{Code: 0x123, Format: "hexadecimal"}, {Code: 123, Format: "decimal"}},
+		},
+		{
+			name:     "negative",
+			input:    "-1073741819",
+			expected: []Interpretation{{Code: 0xC0000005, Format: "signed decimal"}},
+		},
+		{
+			name:     "WinDbg decimal",
+			input:    "0n5",
+			expected: []Interpretation{{Code: 5, Format: "decimal (WinDbg 0n prefix)"}},
+		},
+		{
+			name:     "WinDbg 64-bit value",
+			input:    "0xffffffff`c0000005",
+			expected: []Interpretation{{Code: 0xC0000005, Format: "hexadecimal (WinDbg 64-bit value, sign-extended from 64 bits)"}},
+		},
+		{
+			name:     "C suffix",
+			input:    "0x80070005L",
+			expected: []Interpretation{{Code: 0x80070005, Format: "hexadecimal (C suffix L)"}},
+		},
+		{
+			name:     "h suffix",
+			input:    "c0000005h",
+			expected: []Interpretation{{Code: 0xC0000005, Format: "hexadecimal (h suffix)"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := InterpretCode(tt.input)
+
+			if err != nil {
+				t.Fatalf("InterpretCode(%q) unexpected error: %v", tt.input, err)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("InterpretCode(%q) = %+v, expected %+v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseParameter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected uint64
+		wantErr  bool
+	}{
+		{name: "hex prefix", input: "0x9F", expected: 0x9F},
+		{name: "bare hex", input: "ffffe0012ad7e880", expected: 0xFFFFE0012AD7E880},
+		{name: "WinDbg 64-bit value", input: "ffffe001`2ad7e880", expected: 0xFFFFE0012AD7E880},
+		{name: "WinDbg decimal", input: "0n10", expected: 10},
+		{name: "spaces", input: " 0x3 ", expected: 3},
+		{name: "empty", input: "", wantErr: true},
+		{name: "too long", input: "0x1ffffffffffffffff", wantErr: true},
+		{name: "garbage", input: "xyz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseParameter(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseParameter(%q) = 0x%X, expected error", tt.input, result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseParameter(%q) unexpected error: %v", tt.input, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("ParseParameter(%q) = 0x%X, expected 0x%X", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestBoolToInt(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Version describes the build of the program from the version control
+// information the Go toolchain embeds in binaries built from a checkout.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+
+	if !ok {
+		return "unknown"
+	}
+
+	return formatVersion(info.Settings)
+}
+
+func formatVersion(settings []debug.BuildSetting) string {
+	var revision, time string
+	modified := false
+
+	for _, setting := range settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			time = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+
+	if revision == "" {
+		return "unknown"
+	}
+
+	version := revision[:min(len(revision), 12)]
+	details := []string{}
+
+	if time != "" {
+		details = append(details, time)
+	}
+
+	if modified {
+		details = append(details, "modified")
+	}
+
+	if len(details) > 0 {
+		version += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	return version
+}
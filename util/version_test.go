@@ -0,0 +1,45 @@
+package util
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestFormatVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []debug.BuildSetting
+		expected string
+	}{
+		{
+			name: "clean checkout",
+			settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "814a590f2c3e4d5b6a7980a1b2c3d4e5f6a7b8c9"},
+				{Key: "vcs.time", Value: "2026-10-17T12:00:00Z"},
+				{Key: "vcs.modified", Value: "false"},
+			},
+			expected: "814a590f2c3e (2026-10-17T12:00:00Z)",
+		},
+		{
+			name: "modified checkout",
+			settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "814a590f2c3e4d5b6a7980a1b2c3d4e5f6a7b8c9"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+			expected: "814a590f2c3e (modified)",
+		},
+		{
+			name:     "no version control information",
+			settings: []debug.BuildSetting{{Key: "GOOS", Value: "linux"}},
+			expected: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatVersion(tt.settings); result != tt.expected {
+				t.Errorf("formatVersion() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package winerror
 type HResult uint32
 
 const FACILITY_WIN32 uint16 = 7
+const FACILITY_WINDOWSUPDATE uint16 = 36
 const FACILITY_NT_BIT uint32 = 0x10000000
 
 func (hr HResult) S() bool {
@@ -32,3 +33,25 @@ func (hr HResult) Facility() uint16 {
 func (hr HResult) Code() uint16 {
 	return uint16(hr & 0xFFFF)
 }
+
+// HResultFromWin32 maps a Win32 error code to an HRESULT like the
+// HRESULT_FROM_WIN32 macro does.
+func HResultFromWin32(code uint32) HResult {
+	if int32(code) <= 0 {
+		return HResult(code)
+	}
+
+	return HResult(code&0x0000FFFF | uint32(FACILITY_WIN32)<<16 | 0x80000000)
+}
+
+// HResultFromNT maps an NTSTATUS code to an HRESULT like the HRESULT_FROM_NT
+// macro does.
+func HResultFromNT(status uint32) HResult {
+	return HResult(status | FACILITY_NT_BIT)
+}
+
+// MakeHResult builds an HRESULT from its parts like the MAKE_HRESULT macro
+// does.
+func MakeHResult(severity uint32, facility uint32, code uint32) HResult {
+	return HResult(severity<<31 | facility<<16 | code)
+}
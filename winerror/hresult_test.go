@@ -537,3 +537,103 @@ func ExampleHResult_Code() {
 	hr := HResult(0x80070005) // E_ACCESSDENIED
 	println(hr.Code())        // 5 (ERROR_ACCESS_DENIED)
 }
+
+func TestHResultFromWin32(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected HResult
+	}{
+		{
+			name:     "ERROR_ACCESS_DENIED",
+			code:     5,
+			expected: HResult(0x80070005),
+		},
+		{
+			name:     "ERROR_SUCCESS stays zero",
+			code:     0,
+			expected: HResult(0),
+		},
+		{
+			name:     "already an HRESULT",
+			code:     0x80004005,
+			expected: HResult(0x80004005),
+		},
+		{
+			name:     "only the low 16 bits are kept",
+			code:     0x00012345,
+			expected: HResult(0x80072345),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HResultFromWin32(tt.code)
+			if result != tt.expected {
+				t.Errorf("HResultFromWin32(%d) = 0x%08X, expected 0x%08X", tt.code, uint32(result), uint32(tt.expected))
+			}
+		})
+	}
+}
+
+func TestHResultFromNT(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   uint32
+		expected HResult
+	}{
+		{
+			name:     "STATUS_ACCESS_VIOLATION",
+			status:   0xC0000005,
+			expected: HResult(0xD0000005),
+		},
+		{
+			name:     "STATUS_SUCCESS",
+			status:   0,
+			expected: HResult(0x10000000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HResultFromNT(tt.status)
+			if result != tt.expected {
+				t.Errorf("HResultFromNT(0x%08X) = 0x%08X, expected 0x%08X", tt.status, uint32(result), uint32(tt.expected))
+			}
+		})
+	}
+}
+
+func TestMakeHResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity uint32
+		facility uint32
+		code     uint32
+		expected HResult
+	}{
+		{
+			name:     "E_ACCESSDENIED",
+			severity: 1,
+			facility: uint32(FACILITY_WIN32),
+			code:     5,
+			expected: HResult(0x80070005),
+		},
+		{
+			name:     "success",
+			severity: 0,
+			facility: 4,
+			code:     0x200,
+			expected: HResult(0x00040200),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MakeHResult(tt.severity, tt.facility, tt.code)
+			if result != tt.expected {
+				t.Errorf("MakeHResult(%d, %d, %d) = 0x%08X, expected 0x%08X", tt.severity, tt.facility, tt.code, uint32(result), uint32(tt.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package winerror
+
+// Kind identifies the kind of an error code, which tells the catalogs it is
+// looked up in.
+type Kind int
+
+const (
+	KindBugCheck Kind = iota
+	KindHResult
+	KindWin32Error
+	KindNTStatus
+)
+
+// Kinds lists all kinds in the order results are presented in.
+var Kinds = []Kind{KindBugCheck, KindHResult, KindWin32Error, KindNTStatus}
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindBugCheck:
+		return "bug check"
+	case KindHResult:
+		return "HRESULT"
+	case KindWin32Error:
+		return "Win32 error"
+	case KindNTStatus:
+		return "NTSTATUS"
+	}
+
+	return "unknown"
+}
@@ -30,3 +30,13 @@ func (status NTStatus) Facility() uint16 {
 func (status NTStatus) Code() uint16 {
 	return uint16(status & 0x0000FFFF)
 }
+
+// NTStatusFromWin32 maps a Win32 error code to an NTSTATUS like the
+// NTSTATUS_FROM_WIN32 macro does.
+func NTStatusFromWin32(code uint32) NTStatus {
+	if int32(code) <= 0 {
+		return NTStatus(code)
+	}
+
+	return NTStatus(code&0x0000FFFF | uint32(FACILITY_NTWIN32)<<16 | uint32(STATUS_SEVERITY_ERROR)<<30)
+}
@@ -516,3 +516,36 @@ func ExampleNTStatus_Code() {
 	status := NTStatus(0xC0000001) // STATUS_UNSUCCESSFUL
 	println(status.Code())         // 1
 }
+
+func TestNTStatusFromWin32(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected NTStatus
+	}{
+		{
+			name:     "ERROR_ACCESS_DENIED",
+			code:     5,
+			expected: NTStatus(0xC0070005),
+		},
+		{
+			name:     "ERROR_SUCCESS stays zero",
+			code:     0,
+			expected: NTStatus(0),
+		},
+		{
+			name:     "already an NTSTATUS",
+			code:     0xC0000005,
+			expected: NTStatus(0xC0000005),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NTStatusFromWin32(tt.code)
+			if result != tt.expected {
+				t.Errorf("NTStatusFromWin32(%d) = 0x%08X, expected 0x%08X", tt.code, uint32(result), uint32(tt.expected))
+			}
+		})
+	}
+}
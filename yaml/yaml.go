@@ -0,0 +1,8 @@
+// Package yaml embeds the error catalogs so that the bot and the tools do not
+// depend on the working directory they are started from.
+package yaml
+
+import "embed"
+
+//go:embed *.yml
+var FS embed.FS